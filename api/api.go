@@ -32,11 +32,13 @@ const (
 	VersionPath            = "/version/cli"
 	HealthzPath            = "/healthz"
 	RestartPath            = "/services/{dockerid}/restart?token={token}"
+	ServiceStatusPath      = "/services/{dockerid}/status?token={token}"
 	SavePath               = "/services/{appid}/save"
 	RemovePath             = "/services/{dockerid}/remove?token={token}"
 	DownloadPath           = "http://download.bowery.io/{version}_{os}_{arch}.zip"
 	BoweryImagesSearchPath = "/images/search/{name}"
 	BoweryImagesCheckPath  = "/images/{name}"
+	LogsPath               = "/applications/{id}/logs?token={token}"
 )
 
 func init() {
@@ -233,6 +233,36 @@ func RestartService(dockerId, token string) (*schemas.Service, error) {
 	return nil, errors.NewStackError(errors.New(errors.ErrFailedRestart, restartRes))
 }
 
+// GetServiceStatus retrieves the state of the given service's docker
+// container, and when it was last restarted.
+func GetServiceStatus(dockerId, token string) (*responses.ServiceStatusRes, error) {
+	endpoint := BasePath + strings.Replace(ServiceStatusPath, "{dockerid}", dockerId, -1)
+	endpoint = strings.Replace(endpoint, "{token}", token, -1)
+	res, err := http.Get(endpoint)
+	if err != nil {
+		return nil, errors.NewStackError(err)
+	}
+	defer res.Body.Close()
+
+	statusRes := new(responses.ServiceStatusRes)
+	decoder := json.NewDecoder(res.Body)
+	err = decoder.Decode(statusRes)
+	if err != nil {
+		return nil, errors.NewStackError(err)
+	}
+
+	if statusRes.Status == "found" {
+		return statusRes, nil
+	}
+
+	if strings.Contains(statusRes.Error(), "Invalid Token") {
+		return nil, errors.ErrInvalidToken
+	}
+
+	// Non "found" status indicates error.
+	return nil, errors.NewStackError(statusRes)
+}
+
 func RemoveService(dockerId, token string) error {
 	endpoint := BasePath + strings.Replace(RemovePath, "{dockerid}", dockerId, -1)
 	endpoint = strings.Replace(endpoint, "{token}", token, -1)
@@ -8,20 +8,27 @@ import (
 
 	"bitbucket.org/kardianos/osext"
 	. "github.com/Bowery/bowery/cmds"
+	"github.com/Bowery/bowery/db"
 	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/bowery/prompt"
 	"github.com/Bowery/bowery/rollbar"
 	"github.com/Bowery/gopackages/keen"
 	"github.com/Bowery/gopackages/log"
 )
 
 var (
-	env   = os.Getenv("ENV")
-	force bool
+	env            = os.Getenv("ENV")
+	force          bool
+	jsonOutput     bool
+	nonInteractive bool
 )
 
 func init() {
 	flag.BoolVar(&force, "force", false, "Force pull.")
 	flag.BoolVar(&force, "f", false, "Force pull.")
+	flag.BoolVar(&jsonOutput, "json", false, "Print JSON.")
+	flag.BoolVar(&nonInteractive, "yes", false, "Answer prompts without input.")
+	flag.BoolVar(&nonInteractive, "non-interactive", false, "Answer prompts without input.")
 }
 
 func main() {
@@ -59,15 +66,44 @@ func main() {
 		args = args[1:]
 	}
 
-	// Run command, and handle invalid commands.
-	cmd, ok := Cmds[command]
+	// Expand aliases from the developers config, they can't replace commands
+	// or plugins.
+	if _, ok := LookupCmd(command); !ok {
+		dev, _ := db.GetDeveloper()
+		expanded, err := ExpandAliases(dev.Aliases, append([]string{command}, args...))
+		if err != nil {
+			log.Fprintln(os.Stderr, "red", err)
+			os.Exit(1)
+		}
+
+		// A blank alias expands to nothing.
+		if len(expanded) <= 0 {
+			log.Fprintln(os.Stderr, "red", errors.ErrInvalidCommand, command)
+			os.Exit(1)
+		}
+
+		command = expanded[0]
+		args = expanded[1:]
+	}
+
+	// Run command, and handle invalid commands. Plugins on the PATH are
+	// commands too.
+	cmd, ok := LookupCmd(command)
 	if !ok {
 		keen.AddEvent("invalid command", map[string]string{"command": command})
+		AddPlugins()
 
 		log.Fprintln(os.Stderr, "red", errors.ErrInvalidCommand, command)
+		if PrintSuggestions(os.Stderr, command, Names(Cmds)) {
+			log.Fprintln(os.Stderr, "", "\nRun `bowery help` to list the commands.")
+			os.Exit(2) // --help uses 2.
+		}
+
 		os.Exit(Cmds["help"].Run(keen, rollbar))
 	}
 
 	cmd.Force = force
-	os.Exit(cmd.Run(keen, rollbar, args...))
+	cmd.JSON = jsonOutput
+	prompt.NonInteractive = nonInteractive
+	os.Exit(cmd.Execute(keen, rollbar, args...))
 }
@@ -2,6 +2,9 @@
 package cmds
 
 import (
+	"flag"
+	"fmt"
+	"os"
 	"strconv"
 	"strings"
 
@@ -15,21 +18,63 @@ import (
 )
 
 func init() {
-	Cmds["add"] = &Cmd{
+	cmd := &Cmd{
 		Run:   addRun,
-		Usage: "add [names]",
+		Usage: "add [names] [-image name] [-path path] [-port port] [-start cmd] [-build cmd] [-test cmd]",
 		Short: "Add services to your application.",
+		Flags: flag.NewFlagSet("add", flag.ContinueOnError),
 	}
+	cmd.Description = "Adds services to your application, asking for each services image, path,\n" +
+		"ports and commands. If any options are given the named services are added\n" +
+		"with them instead, e.g. `bowery add web -image node -path ./web:/app\n" +
+		"-port 3000 -start \"node app.js\"`. Existing services are only replaced\n" +
+		"with `bowery --force add`."
+	cmd.Flags.String("image", "", "The image the service uses, defaults to base.")
+	cmd.Flags.String("path", "", "The local path to sync, and remote path after a colon.")
+	cmd.Flags.Var(new(portsFlag), "port", "A port to expose, may be repeated or comma separated.")
+	cmd.Flags.String("start", "", "The command that starts the service.")
+	cmd.Flags.String("build", "", "The command that builds the service.")
+	cmd.Flags.String("test", "", "The command that tests the service.")
+
+	Cmds["add"] = cmd
+}
+
+// portsFlag is a flag for ports that can be repeated.
+type portsFlag []string
+
+func (ports *portsFlag) String() string {
+	return strings.Join(*ports, ",")
+}
+
+func (ports *portsFlag) Set(value string) error {
+	*ports = append(*ports, value)
+	return nil
 }
 
 func addRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
+	cmd := Cmds["add"]
 	services, err := db.GetServices()
 	if err != nil {
 		rollbar.Report(err)
 		return 1
 	}
 
-	err = addServices(services, args...)
+	// Only prompt if no options were given.
+	hasFlags := false
+	cmd.Flags.Visit(func(*flag.Flag) {
+		hasFlags = true
+	})
+
+	if hasFlags {
+		if len(args) <= 0 {
+			fmt.Fprintln(os.Stderr, "Usage: bowery "+cmd.Usage, "\n\n"+cmd.Short)
+			return 2 // --help uses 2.
+		}
+
+		err = addServicesFromFlags(cmd, services, args...)
+	} else {
+		err = addServices(services, args...)
+	}
 	if err != nil {
 		rollbar.Report(err)
 		return 1
@@ -65,11 +110,7 @@ func addServices(services *db.Services, names ...string) error {
 			}
 		}
 
-		// Normalize name
-		name = strings.ToLower(name)
-		for _, c := range "\f\n\r\t\v\u00A0\u2028\u2029" {
-			name = strings.Replace(name, string(c), "-", -1)
-		}
+		name = normalizeName(name)
 
 		// If name already exists, prompt for overwrite.
 		_, ok := services.Data[name]
@@ -147,21 +188,9 @@ func addServices(services *db.Services, names ...string) error {
 		if err != nil {
 			return err
 		}
-		var portsList []interface{}
-
-		// Create list of int ports.
-		if ports != "" {
-			portsSplit := strings.Split(ports, ",")
-			portsList = make([]interface{}, len(portsSplit))
-			for i, port := range portsSplit {
-				port = strings.Trim(port, " ")
-				num, err := strconv.Atoi(port)
-				if err != nil {
-					return errors.Newf(errors.ErrInvalidPortTmpl, port)
-				}
-
-				portsList[i] = num
-			}
+		portsList, err := parsePorts(ports)
+		if err != nil {
+			return err
 		}
 
 		// Ask for start
@@ -209,3 +238,88 @@ func addServices(services *db.Services, names ...string) error {
 	log.Debug("Saving services", services.Data)
 	return services.Save()
 }
+
+// addServicesFromFlags adds services with the options from the flags and
+// saves them, without prompting.
+func addServicesFromFlags(cmd *Cmd, services *db.Services, names ...string) error {
+	image := cmd.flagString("image")
+	if image == "" {
+		image = "base"
+	}
+
+	if image != "base" {
+		err := api.FindImage(image)
+		if err != nil {
+			if err == errors.ErrNoImageFound {
+				log.Println("yellow", "Try another image. Search for them using `bowery search`.")
+			}
+
+			return err
+		}
+	}
+
+	// Sync to the same default remote path as the wizard.
+	path := cmd.flagString("path")
+	if path != "" && !strings.Contains(path, ":") {
+		path += ":/application"
+	}
+
+	ports, err := parsePorts(cmd.flagString("port"))
+	if err != nil {
+		return err
+	}
+
+	start, build, test := cmd.flagString("start"), cmd.flagString("build"), cmd.flagString("test")
+	for _, name := range names {
+		name = normalizeName(name)
+
+		_, ok := services.Data[name]
+		if ok && !cmd.Force {
+			return errors.Newf(errors.ErrServiceExistsTmpl, name)
+		}
+
+		log.Debug("Adding service", "name", name, "image", image, "path", path, "ports", ports, "start", start, "build", build, "test", test)
+		services.Data[name] = &db.Service{
+			Image: image,
+			Path:  path,
+			Ports: ports,
+			Start: start,
+			Build: build,
+			Test:  test,
+		}
+	}
+
+	log.Debug("Saving services", services.Data)
+	return services.Save()
+}
+
+// normalizeName replaces whitespace in a service name with dashes.
+func normalizeName(name string) string {
+	name = strings.ToLower(name)
+	for _, c := range "\f\n\r\t\v\u00A0\u2028\u2029" {
+		name = strings.Replace(name, string(c), "-", -1)
+	}
+
+	return name
+}
+
+// parsePorts parses a comma separated list of ports.
+func parsePorts(ports string) ([]interface{}, error) {
+	if ports == "" {
+		return nil, nil
+	}
+
+	portsSplit := strings.Split(ports, ",")
+	portsList := make([]interface{}, len(portsSplit))
+	for i, port := range portsSplit {
+		port = strings.Trim(port, " ")
+		num, err := strconv.Atoi(port)
+		if err != nil {
+			return nil, errors.Newf(errors.ErrInvalidPortTmpl, port)
+		}
+
+		portsList[i] = num
+	}
+
+	return portsList, nil
+}
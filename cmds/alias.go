@@ -0,0 +1,199 @@
+// Copyright 2013-2014 Bowery, Inc.
+package cmds
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Bowery/bowery/db"
+	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/bowery/prompt"
+	"github.com/Bowery/bowery/rollbar"
+	"github.com/Bowery/gopackages/keen"
+	"github.com/Bowery/gopackages/log"
+)
+
+// ExpandAliases replaces the command in args while it's an alias, e.g.
+// "deploy" with "save web" for `deploy = save web`. Aliases are split into
+// arguments like a shell would, see splitArgs. Arguments after the command
+// are kept.
+func ExpandAliases(aliases map[string]string, args []string) ([]string, error) {
+	seen := make(map[string]bool)
+
+	for len(args) > 0 {
+		alias, ok := aliases[args[0]]
+		if !ok {
+			break
+		}
+		if seen[args[0]] {
+			return nil, errors.Newf(errors.ErrRecursiveAliasTmpl, args[0])
+		}
+		seen[args[0]] = true
+
+		args = append(splitArgs(alias), args[1:]...)
+	}
+
+	return args, nil
+}
+
+func aliasRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
+	dev, err := db.GetDeveloper()
+	if err != nil && err != errors.ErrNoDeveloper {
+		rollbar.Report(err)
+		return 1
+	}
+
+	if len(args) <= 0 {
+		printAliases(dev.Aliases)
+		return 0
+	}
+	name := args[0]
+	command := ""
+
+	if _, ok := LookupCmd(name); ok {
+		log.Fprintln(os.Stderr, "red", errors.Newf(errors.ErrAliasCommandTmpl, name))
+		return 1
+	}
+
+	// Allow `deploy = save web`.
+	args = args[1:]
+	if len(args) > 0 && args[0] == "=" {
+		args = args[1:]
+	}
+
+	if len(args) <= 0 {
+		command, err = prompt.Basic("Command", false)
+		if err != nil {
+			rollbar.Report(err)
+			return 1
+		}
+	} else {
+		command = joinArgs(args)
+	}
+
+	if dev.Aliases == nil {
+		dev.Aliases = make(map[string]string)
+	}
+
+	if strings.TrimSpace(command) == "" {
+		delete(dev.Aliases, name)
+	} else {
+		dev.Aliases[name] = command
+
+		// Ensure the alias expands to a command.
+		expanded, err := ExpandAliases(dev.Aliases, []string{name})
+		if err != nil {
+			log.Fprintln(os.Stderr, "red", err)
+			return 1
+		}
+
+		if len(expanded) <= 0 {
+			log.Fprintln(os.Stderr, "red", errors.ErrInvalidCommand, name)
+			return 1
+		}
+
+		if _, ok := LookupCmd(expanded[0]); !ok {
+			log.Fprintln(os.Stderr, "red", errors.ErrInvalidCommand, expanded[0])
+			PrintSuggestions(os.Stderr, expanded[0], Names(Cmds))
+			return 1
+		}
+	}
+
+	err = dev.Save()
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+
+	keen.AddEvent("bowery config alias", map[string]string{"name": name, "command": command})
+	return 0
+}
+
+// joinArgs joins arguments into a command, quoting the ones splitArgs
+// would split or change.
+func joinArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = arg
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"\\") {
+			quoted[i] = "'" + strings.Replace(arg, "'", `'\''`, -1) + "'"
+		}
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// splitArgs splits a command into arguments at whitespace. Like a shell,
+// single quotes keep everything inside them, double quotes keep everything
+// but backslash escapes, and a backslash escapes the next character. An
+// unterminated quote runs to the end.
+func splitArgs(command string) []string {
+	args := make([]string, 0)
+	var arg bytes.Buffer
+	inArg := false
+	quote := byte(0)
+
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				arg.WriteByte(c)
+			}
+		case c == '\\' && i+1 < len(command) &&
+			(quote == 0 || command[i+1] == '"' || command[i+1] == '\\'):
+			i++
+			arg.WriteByte(command[i])
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else {
+				arg.WriteByte(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inArg = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteByte(c)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+
+	return args
+}
+
+// printAliases prints the aliases sorted by name.
+func printAliases(aliases map[string]string) {
+	if len(aliases) <= 0 {
+		log.Println("yellow", "No aliases. Add one with `bowery config alias <name> <command>`.")
+		return
+	}
+
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tabWriter := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintln(tabWriter, name+"\t= "+aliases[name])
+	}
+	tabWriter.Flush()
+}
@@ -2,6 +2,8 @@
 package cmds
 
 import (
+	"os"
+
 	"github.com/Bowery/bowery/api"
 	"github.com/Bowery/bowery/db"
 	"github.com/Bowery/bowery/rollbar"
@@ -17,7 +19,15 @@ func init() {
 	}
 }
 
+// appInfo is an app printed by `bowery --json apps`.
+type appInfo struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
 func appsRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
+	jsonOutput := Cmds["apps"].JSON
+
 	dev, err := getDeveloper()
 	if err != nil {
 		rollbar.Report(err)
@@ -25,13 +35,31 @@ func appsRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
 	}
 
 	// Fetch apps.
-	log.Print("magenta", "Requesting apps... ")
+	if !jsonOutput {
+		log.Print("magenta", "Requesting apps... ")
+	}
 	apps, err := api.GetApps(dev.Token)
 	if err != nil {
 		rollbar.Report(err)
 		return 1
 	}
 	log.Debug("Founds apps", apps)
+	keen.AddEvent("bowery apps", map[string]*db.Developer{"user": dev})
+
+	if jsonOutput {
+		infos := make([]*appInfo, len(apps))
+		for i, app := range apps {
+			infos[i] = &appInfo{ID: app.ID, Name: app.Name}
+		}
+
+		err = writeJSON(os.Stdout, infos)
+		if err != nil {
+			rollbar.Report(err)
+			return 1
+		}
+
+		return 0
+	}
 
 	if len(apps) <= 0 {
 		log.Println("", "No apps were found.")
@@ -48,6 +76,5 @@ func appsRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
 
 	log.Println("magenta", "\nIf you'd like to start working on one of these apps run:\n")
 	log.Println("magenta", "  $ bowery pull <app name or id>\n")
-	keen.AddEvent("bowery apps", map[string]*db.Developer{"user": dev})
 	return 0
 }
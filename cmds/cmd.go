@@ -3,8 +3,17 @@
 package cmds
 
 import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Bowery/bowery/errors"
 	"github.com/Bowery/bowery/rollbar"
 	"github.com/Bowery/gopackages/keen"
+	"github.com/Bowery/gopackages/log"
 )
 
 // Cmds are a map of commands by name.
@@ -27,6 +36,173 @@ type Cmd struct {
 	// Detailed description. Used in help page.
 	Description string
 
+	// Flags the command accepts, parsed before Run is called. The flags are
+	// listed in the help page.
+	Flags *flag.FlagSet
+
+	// Only parse flags before the first argument, the remaining arguments are
+	// given to Run as is, e.g. a command to run on a service.
+	FlagsFirst bool
+
+	// Subcommands by name, e.g. "password" for `bowery settings password`.
+	// Run is optional for commands with subcommands, it's used if no
+	// subcommand matches.
+	Subcmds map[string]*Cmd
+
+	// Complete gets the shell completions for the argument after args, e.g.
+	// service names. Subcommands and flags are completed without it.
+	Complete func(args ...string) []string
+
+	// Hidden commands aren't listed in help or completions, e.g. commands
+	// used by scripts.
+	Hidden bool
+
 	// Force command line flag.
 	Force bool
+
+	// JSON command line flag, the command prints a JSON document to stdout
+	// instead of text. Diagnostics still go to stderr.
+	JSON bool
+}
+
+// Execute runs the subcommand named by the first argument, or parses the
+// commands flags and runs it with the remaining arguments. Flags may be given
+// anywhere in the arguments unless FlagsFirst is set, "--" ends the flags.
+func (cmd *Cmd) Execute(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
+	if len(cmd.Subcmds) > 0 {
+		if len(args) > 0 {
+			subcmd, ok := cmd.Subcmds[args[0]]
+			if ok {
+				subcmd.Force = cmd.Force
+				subcmd.JSON = cmd.JSON
+				return subcmd.Execute(keen, rollbar, args[1:]...)
+			}
+		}
+
+		if cmd.Run == nil {
+			// An invalid choice is an error, only usage uses 2.
+			if len(args) > 0 {
+				log.Fprintln(os.Stderr, "red", errors.ErrInvalidCommand, args[0])
+				PrintSuggestions(os.Stderr, args[0], Names(cmd.Subcmds))
+				return 1
+			}
+
+			fmt.Fprintln(os.Stderr, "Usage: bowery "+cmd.Usage, "\n\n"+cmd.Short)
+			cmd.PrintSubcmds(os.Stderr)
+			return 2 // --help uses 2.
+		}
+	}
+
+	if cmd.Flags == nil {
+		return cmd.Run(keen, rollbar, args...)
+	}
+	cmd.Flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: bowery "+cmd.Usage, "\n\n"+cmd.Short)
+	}
+
+	args, err := cmd.parseFlags(args)
+	if err != nil {
+		return 2 // --help uses 2.
+	}
+
+	return cmd.Run(keen, rollbar, args...)
+}
+
+// parseFlags parses the flags in args, returning the other arguments.
+func (cmd *Cmd) parseFlags(args []string) ([]string, error) {
+	if cmd.FlagsFirst {
+		err := cmd.Flags.Parse(args)
+		if err != nil {
+			return nil, err
+		}
+
+		return cmd.Flags.Args(), nil
+	}
+
+	// Arguments after "--" aren't flags.
+	tail := []string{}
+	for i, arg := range args {
+		if arg == "--" {
+			args, tail = args[:i], args[i+1:]
+			break
+		}
+	}
+
+	// Parse stops at the first argument, so parse until there's none left.
+	rest := make([]string, 0, len(args))
+	for {
+		err := cmd.Flags.Parse(args)
+		if err != nil {
+			return nil, err
+		}
+		args = cmd.Flags.Args()
+		if len(args) <= 0 {
+			break
+		}
+
+		rest = append(rest, args[0])
+		args = args[1:]
+	}
+
+	return append(rest, tail...), nil
+}
+
+// flagString gets the value of one of the commands flags.
+func (cmd *Cmd) flagString(name string) string {
+	return cmd.Flags.Lookup(name).Value.String()
+}
+
+// flagBool gets the value of one of the commands boolean flags.
+func (cmd *Cmd) flagBool(name string) bool {
+	return cmd.flagString(name) == "true"
+}
+
+// PrintSubcmds writes the commands subcommands and their descriptions to
+// out, sorted by name.
+func (cmd *Cmd) PrintSubcmds(out io.Writer) {
+	if len(cmd.Subcmds) <= 0 {
+		return
+	}
+
+	tabWriter := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tabWriter, "\nCommands:")
+	for _, name := range Names(cmd.Subcmds) {
+		subcmd := cmd.Subcmds[name]
+		fmt.Fprintln(tabWriter, "  "+subcmd.Usage+"\t"+subcmd.Short)
+	}
+	tabWriter.Flush()
+}
+
+// PrintFlags writes the commands flags and their descriptions to out.
+func (cmd *Cmd) PrintFlags(out io.Writer) {
+	if cmd.Flags == nil {
+		return
+	}
+
+	tabWriter := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tabWriter, "\nOptions:")
+	cmd.Flags.VisitAll(func(f *flag.Flag) {
+		line := "  -" + f.Name + "\t" + f.Usage
+		if f.DefValue != "" && f.DefValue != "false" {
+			line += " Defaults to " + f.DefValue + "."
+		}
+
+		fmt.Fprintln(tabWriter, line)
+	})
+	tabWriter.Flush()
+}
+
+// writeJSON writes v to out as indented JSON, for commands given --json.
+func writeJSON(out io.Writer, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+
+	_, err = out.Write(append(data, '\n'))
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+
+	return nil
 }
@@ -2,12 +2,352 @@
 package cmds
 
 import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
 	"testing"
+	"time"
+
+	"github.com/Bowery/bowery/db"
+	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/bowery/rollbar"
+	"github.com/Bowery/gopackages/keen"
+	"github.com/Bowery/gopackages/schemas"
 )
 
+// cmd.go
+func TestParseFlags(t *testing.T) {
+	cmd := &Cmd{Flags: flag.NewFlagSet("test", flag.ContinueOnError)}
+	cmd.Flags.String("since", "", "")
+	cmd.Flags.Bool("json", false, "")
+
+	args, err := cmd.parseFlags([]string{"web", "-since", "10m", "db", "-json", "--", "-x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	since, json := cmd.flagString("since"), cmd.flagBool("json")
+	if since != "10m" || !json || !reflect.DeepEqual(args, []string{"web", "db", "-x"}) {
+		t.Error("parseFlags parsed invalid flags", since, json, args)
+	}
+
+	cmd = &Cmd{Flags: flag.NewFlagSet("test", flag.ContinueOnError), FlagsFirst: true}
+	cmd.Flags.Bool("t", false, "")
+
+	args, err = cmd.parseFlags([]string{"-t", "web", "ls", "-la"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmd.flagBool("t") || !reflect.DeepEqual(args, []string{"web", "ls", "-la"}) {
+		t.Error("parseFlags should stop at the first argument", args)
+	}
+}
+
+// cmd.go
+func TestExecuteSubcmd(t *testing.T) {
+	var ran []string
+	cmd := &Cmd{
+		Subcmds: map[string]*Cmd{
+			"password": &Cmd{Run: func(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
+				ran = args
+				return 3
+			}},
+		},
+	}
+
+	if status := cmd.Execute(nil, nil, "password", "now"); status != 3 || !reflect.DeepEqual(ran, []string{"now"}) {
+		t.Error("Execute didn't run the subcommand", status, ran)
+	}
+
+	if status := cmd.Execute(nil, nil); status != 2 {
+		t.Error("Execute should print usage without a subcommand, got", status)
+	}
+
+	if status := cmd.Execute(nil, nil, "username"); status != 1 {
+		t.Error("Execute should fail for an invalid subcommand, got", status)
+	}
+}
+
+// config.go
+func TestConfigInvalidKey(t *testing.T) {
+	if status := Cmds["config"].Execute(nil, nil, "colour", "red"); status != 1 {
+		t.Error("config should fail for an invalid key, got", status)
+	}
+
+	if status := Cmds["config"].Execute(nil, nil); status != 2 {
+		t.Error("config should print usage without a key, got", status)
+	}
+}
+
+// alias.go
+func TestExpandAliases(t *testing.T) {
+	aliases := map[string]string{"c": "connect", "deploy": "save web", "d": "deploy", "a": "b", "b": "a"}
+
+	args, err := ExpandAliases(aliases, []string{"d", "-f"})
+	if err != nil || !reflect.DeepEqual(args, []string{"save", "web", "-f"}) {
+		t.Error("ExpandAliases should expand nested aliases", args, err)
+	}
+
+	args, err = ExpandAliases(aliases, []string{"status"})
+	if err != nil || !reflect.DeepEqual(args, []string{"status"}) {
+		t.Error("ExpandAliases should keep commands", args, err)
+	}
+
+	if _, err = ExpandAliases(aliases, []string{"a"}); err == nil {
+		t.Error("ExpandAliases should fail for recursive aliases.")
+	}
+
+	args, err = ExpandAliases(map[string]string{"blank": " ", "b": "blank"}, []string{"b"})
+	if err != nil || len(args) != 0 {
+		t.Error("ExpandAliases should give no arguments for blank aliases", args, err)
+	}
+}
+
+// alias.go
+func TestJoinArgs(t *testing.T) {
+	args := []string{"exec", "web", "--", "sh", "-c", "echo \"it's\" > $OUT", ""}
+
+	command := joinArgs(args)
+	if command != `exec web -- sh -c 'echo "it'\''s" > $OUT' ''` {
+		t.Error("joinArgs quoted invalid arguments", command)
+	}
+	if split := splitArgs(command); !reflect.DeepEqual(split, args) {
+		t.Error("splitArgs should split joined arguments", split)
+	}
+
+	split := splitArgs(`save  "web app" a\ b "say \"hi\"" 'unterminated`)
+	if !reflect.DeepEqual(split, []string{"save", "web app", "a b", `say "hi"`, "unterminated"}) {
+		t.Error("splitArgs split invalid arguments", split)
+	}
+}
+
+// add.go
+func TestParsePorts(t *testing.T) {
+	ports, err := parsePorts("3000, 8080")
+	if err != nil || !reflect.DeepEqual(ports, []interface{}{3000, 8080}) {
+		t.Error("parsePorts should parse comma separated ports", ports, err)
+	}
+
+	if _, err = parsePorts("3000,http"); err == nil {
+		t.Error("parsePorts should fail for invalid ports.")
+	}
+}
+
+// add.go
+func TestPortsFlag(t *testing.T) {
+	ports := new(portsFlag)
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Var(ports, "port", "")
+
+	err := flags.Parse([]string{"-port", "3000", "-port", "4000,5000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ports.String() != "3000,4000,5000" {
+		t.Error("portsFlag should collect repeated ports, got", ports.String())
+	}
+}
+
+// cp.go
+func TestServicePath(t *testing.T) {
+	services := []*schemas.Service{{Name: "web"}, {Name: "web:api"}}
+
+	service, remote := servicePath(services, "web:api:/app")
+	if service != services[0] || remote != "api:/app" {
+		t.Error("servicePath should use the first service matching, got", service, remote)
+	}
+
+	if service, _ = servicePath(services, "./web:logs"); service != nil {
+		t.Error("servicePath should give no service for local paths, got", service)
+	}
+}
+
+// cmd.go
+func TestWriteJSON(t *testing.T) {
+	var out bytes.Buffer
+
+	err := writeJSON(&out, &appInfo{ID: "1", Name: "web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "{\n  \"id\": \"1\",\n  \"name\": \"web\"\n}\n" {
+		t.Error("writeJSON wrote invalid JSON", out.String())
+	}
+}
+
+// completion.go
+func TestCompletions(t *testing.T) {
+	tests := []struct {
+		words []string
+		want  []string
+	}{
+		{[]string{"sta"}, []string{"status"}},
+		{[]string{"--json", "ver"}, []string{"version"}},
+		{[]string{"--y"}, []string{"--yes"}},
+		{[]string{"config", "logs"}, []string{"logsize", "logsource"}},
+		{[]string{"completion", ""}, []string{"bash", "zsh"}},
+		{[]string{"status", "-w"}, []string{"-watch"}},
+		{[]string{"errors", "1"}, []string{"1", "10", "11", "12", "13", "14", "15", "16", "17", "18", "19"}},
+		{[]string{"__comp"}, []string{}},
+	}
+
+	for _, test := range tests {
+		got := completions(test.words...)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Error("completions for", test.words, "should be", test.want, "got", got)
+		}
+	}
+}
+
+// suggest.go
+func TestSuggest(t *testing.T) {
+	candidates := []string{"connect", "config", "restart", "ssh", "status", "save"}
+	tests := map[string][]string{
+		"statsu":  []string{"status"},
+		"conect":  []string{"connect"},
+		"con":     []string{"config", "connect"},
+		"sh":      []string{"ssh"},
+		"destroy": []string{},
+	}
+
+	for name, want := range tests {
+		got := Suggest(name, candidates)
+		if !reflect.DeepEqual(got, want) {
+			t.Error("Suggest for", name, "should be", want, "got", got)
+		}
+	}
+}
+
+// suggest.go
+func TestNames(t *testing.T) {
+	cmds := map[string]*Cmd{"ssh": &Cmd{}, "add": &Cmd{}, "__complete": &Cmd{Hidden: true}}
+	if names := Names(cmds); !reflect.DeepEqual(names, []string{"add", "ssh"}) {
+		t.Error("Names should be sorted without hidden commands, got", names)
+	}
+}
+
+// errors.go
+func TestNewErrorInfo(t *testing.T) {
+	info := newErrorInfo(errors.Error{Code: "34", Title: errors.ErrInvalidConfigValueTmpl})
+	if info.Code != "34" || info.Title != "%s is an invalid value for %s. Try again." {
+		t.Error("newErrorInfo should remove the code from the title", info.Title)
+	}
+}
+
 // version.go
 func TestVersionOutOfDate(t *testing.T) {
 	if isOutOfDate := VersionOutOfDate("2.0.0", "2.0.1"); !isOutOfDate {
 		t.Error("VersioutOutOfDate failed.")
 	}
 }
+
+// logs.go
+func TestParseSince(t *testing.T) {
+	now := time.Date(2014, 6, 1, 15, 4, 5, 0, time.UTC)
+
+	since, err := parseSince("", now)
+	if err != nil || !since.IsZero() {
+		t.Error("parseSince should give the zero time for an empty string.")
+	}
+
+	since, err = parseSince("10m", now)
+	if err != nil || !since.Equal(now.Add(-10*time.Minute)) {
+		t.Error("parseSince failed to parse a duration.", since, err)
+	}
+
+	since, err = parseSince("2014-06-01T12:00:00Z", now)
+	if err != nil || !since.Equal(time.Date(2014, 6, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Error("parseSince failed to parse a time.", since, err)
+	}
+
+	_, err = parseSince("yesterday", now)
+	if err == nil {
+		t.Error("parseSince should fail for an invalid time.")
+	}
+}
+
+// logs.go
+func TestServiceColor(t *testing.T) {
+	if serviceColor("web") != serviceColor("web") {
+		t.Error("serviceColor should give the same color for a service.")
+	}
+}
+
+// plugins.go
+func TestFindPlugins(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bowery-plugins")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	name := "bowery-seed"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), 0755)
+	if err == nil {
+		err = ioutil.WriteFile(filepath.Join(dir, "bowery-notes.txt"), []byte("notes"), 0644)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plugins := FindPlugins(dir)
+	if len(plugins) != 1 || plugins["seed"] != filepath.Join(dir, name) {
+		t.Error("FindPlugins found invalid plugins", plugins)
+	}
+
+	// Plugins are looked up by name when there's no command.
+	path := os.Getenv("PATH")
+	defer os.Setenv("PATH", path)
+	os.Setenv("PATH", dir)
+	defer delete(Cmds, "seed")
+
+	if cmd, ok := LookupCmd("seed"); !ok || cmd.Short != "Plugin at "+filepath.Join(dir, name)+"." {
+		t.Error("LookupCmd should find the plugin", cmd)
+	}
+	if _, ok := LookupCmd("notes"); ok {
+		t.Error("LookupCmd should skip files that aren't executables.")
+	}
+	if cmd, ok := LookupCmd("help"); !ok || cmd != Cmds["help"] {
+		t.Error("LookupCmd should give commands first", cmd)
+	}
+}
+
+// connect.go
+func TestServiceAddr(t *testing.T) {
+	env := ENV
+	defer func() { ENV = env }()
+	state := &db.State{App: &schemas.Application{ID: "abc", Name: "blog"}}
+	service := &schemas.Service{
+		Name:        "web",
+		PublicAddr:  "127.0.0.1:49153",
+		CustomPorts: map[string]string{"8080": "127.0.0.1:49154"},
+	}
+
+	ENV = "production"
+	if addr := serviceAddr(state, service, ""); addr != "web.blog.boweryapps.com" {
+		t.Error("serviceAddr gave an invalid address", addr)
+	}
+	if addr := serviceAddr(state, service, "8080"); addr != "8080.web.blog.boweryapps.com" {
+		t.Error("serviceAddr gave an invalid port address", addr)
+	}
+	if url := serviceURL(state, "web"); url != "http://web.blog.boweryapps.com" {
+		t.Error("serviceURL gave an invalid url", url)
+	}
+
+	ENV = "development"
+	if addr := serviceAddr(state, service, ""); addr != service.PublicAddr {
+		t.Error("serviceAddr should give the public address in development, got", addr)
+	}
+	if addr := serviceAddr(state, service, "8080"); addr != "127.0.0.1:49154" {
+		t.Error("serviceAddr should give the port address in development, got", addr)
+	}
+	if url := serviceURL(state, "api"); url != "" {
+		t.Error("serviceURL should give nothing for unknown services in development, got", url)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2013-2014 Bowery, Inc.
+package cmds
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Bowery/bowery/api"
+	"github.com/Bowery/bowery/db"
+	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/bowery/rollbar"
+	"github.com/Bowery/gopackages/keen"
+)
+
+// Completion scripts for each shell, they get the completions from
+// `bowery __complete <words>`.
+const (
+	bashCompletion = `# bash completion for bowery, add to ~/.bashrc:
+#   source <(bowery completion bash)
+_bowery() {
+  local IFS=$'\n'
+  COMPREPLY=($(bowery __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _bowery bowery
+`
+
+	zshCompletion = `#compdef bowery
+# zsh completion for bowery, add to ~/.zshrc after compinit:
+#   source <(bowery completion zsh)
+_bowery() {
+  local -a completions
+  completions=("${(@f)$(bowery __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+  compadd -a completions
+}
+compdef _bowery bowery
+`
+)
+
+// globalFlags are the flags completed before the command.
+var globalFlags = []string{"--force", "--json", "--yes", "--non-interactive"}
+
+func init() {
+	cmd := &Cmd{
+		Usage: "completion <shell>",
+		Short: "Print a shell completion script.",
+		Subcmds: map[string]*Cmd{
+			"bash": &Cmd{
+				Run:   completionRun(bashCompletion),
+				Usage: "completion bash",
+				Short: "Print the bash completion script.",
+			},
+			"zsh": &Cmd{
+				Run:   completionRun(zshCompletion),
+				Usage: "completion zsh",
+				Short: "Print the zsh completion script.",
+			},
+		},
+	}
+	cmd.Description = "Prints a script that completes commands, options, service names, app names\n" +
+		"and error codes, e.g. `source <(bowery completion bash)` in ~/.bashrc."
+	Cmds["completion"] = cmd
+
+	Cmds["__complete"] = &Cmd{
+		Run:    completeRun,
+		Usage:  "__complete [words]",
+		Short:  "Print the completions for a command line.",
+		Hidden: true,
+	}
+}
+
+// completionRun creates a runner that prints a completion script.
+func completionRun(script string) func(*keen.Client, *rollbar.Client, ...string) int {
+	return func(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
+		fmt.Print(script)
+		return 0
+	}
+}
+
+func completeRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
+	AddPlugins()
+
+	for _, completion := range completions(args...) {
+		fmt.Println(completion)
+	}
+
+	return 0
+}
+
+// completions gets the completions for the last word, given the words after
+// bowery.
+func completions(words ...string) []string {
+	if len(words) <= 0 {
+		words = []string{""}
+	}
+	word := words[len(words)-1]
+	words = words[:len(words)-1]
+
+	// Skip the global flags before the command.
+	for len(words) > 0 && strings.HasPrefix(words[0], "-") {
+		words = words[1:]
+	}
+
+	candidates := make([]string, 0)
+	if len(words) <= 0 {
+		if strings.HasPrefix(word, "-") {
+			return filterPrefix(globalFlags, word)
+		}
+
+		// Aliases are used like commands.
+		candidates = Names(Cmds)
+		dev, _ := db.GetDeveloper()
+		for name := range dev.Aliases {
+			candidates = append(candidates, name)
+		}
+
+		return filterPrefix(candidates, word)
+	}
+
+	cmd, ok := Cmds[words[0]]
+	if !ok {
+		return nil
+	}
+	args := words[1:]
+
+	// Find the subcommand being completed.
+	for len(args) > 0 {
+		subcmd, ok := cmd.Subcmds[args[0]]
+		if !ok {
+			break
+		}
+
+		cmd = subcmd
+		args = args[1:]
+	}
+
+	switch {
+	case strings.HasPrefix(word, "-") && cmd.Flags != nil:
+		cmd.Flags.VisitAll(func(f *flag.Flag) {
+			candidates = append(candidates, "-"+f.Name)
+		})
+	case len(cmd.Subcmds) > 0 && len(args) <= 0:
+		candidates = Names(cmd.Subcmds)
+	case cmd.Complete != nil:
+		candidates = cmd.Complete(args...)
+	}
+
+	return filterPrefix(candidates, word)
+}
+
+// filterPrefix gets the sorted candidates that start with prefix.
+func filterPrefix(candidates []string, prefix string) []string {
+	matches := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Strings(matches)
+
+	return matches
+}
+
+// completeServices completes the service names in bowery.json.
+func completeServices(args ...string) []string {
+	services, err := db.GetServices()
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(services.Data))
+	for name := range services.Data {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// completeApps completes the developers app names, or ids for apps without
+// a name. Nothing is completed if they aren't logged in.
+func completeApps(args ...string) []string {
+	dev, err := db.GetDeveloper()
+	if err != nil {
+		return nil
+	}
+
+	apps, err := api.GetApps(dev.Token)
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, len(apps))
+	for i, app := range apps {
+		names[i] = app.ID
+		if app.Name != "" {
+			names[i] = app.Name
+		}
+	}
+
+	return names
+}
+
+// completeErrors completes the error codes.
+func completeErrors(args ...string) []string {
+	codes := make([]string, 0)
+	for _, err := range errors.GetAll() {
+		codes = append(codes, err.Code)
+	}
+
+	return codes
+}
@@ -4,53 +4,93 @@ package cmds
 import (
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/Bowery/bowery/db"
 	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/bowery/logs"
 	"github.com/Bowery/bowery/prompt"
 	"github.com/Bowery/bowery/rollbar"
 	"github.com/Bowery/gopackages/keen"
+	"github.com/Bowery/gopackages/log"
 )
 
 func init() {
 	cmd := &Cmd{
-		Run:   configRun,
-		Usage: "config <key> [value]",
-		Short: "Set custom configuration options.",
+		Run:     configKeyRun,
+		Usage:   "config <key> [value]",
+		Short:   "Set custom configuration options.",
+		Subcmds: make(map[string]*Cmd),
+	}
+	cmd.Description = "Sets custom configuration options for connecting to Bowery. If no value is\n" +
+		"given it's prompted for, an empty value removes the option. Aliases for\n" +
+		"commands are set with `bowery config alias <name> <command>`."
+
+	addConfigKey(cmd, "host", "The host bowery is running on.", nil)
+	addConfigKey(cmd, "redis", "The host for a Redis connection.", nil)
+	addConfigKey(cmd, "provider", "The system running bowery.", nil)
+	addConfigKey(cmd, "logsize", "The size in MB .bowery/output.log is rotated at, defaults to 10.",
+		isNumber(1))
+	addConfigKey(cmd, "logfiles", "The number of rotated logs to keep, defaults to 3.", isNumber(0))
+	addConfigKey(cmd, "logsource", "Where logs are streamed from, redis, http or auto (default).",
+		isLogSource)
+	addConfigKey(cmd, "timeout", "Seconds to wait for services to start on connect, defaults to 120.",
+		isNumber(1))
+	addConfigKey(cmd, "hooktimeout", "Seconds a hook can run before it's killed, defaults to 60.",
+		isNumber(1))
+
+	cmd.Subcmds["alias"] = &Cmd{
+		Run:   aliasRun,
+		Usage: "config alias [name] [command]",
+		Short: "Set a shortcut for a command, e.g. `config alias deploy save web`.",
 	}
-	cmd.Description = "Sets custom configuration options for connecting to Bowery." +
-		"\n\nCurrent config options are:" +
-		"\n  host  - The host bowery is running on" +
-		"\n  redis - The host for a Redis connection" +
-		"\n  provider - The system running bowery."
 
 	Cmds["config"] = cmd
 }
 
-func configRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
-	var err error
+// configKeyRun is ran if the key isn't a subcommand of config.
+func configKeyRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
+	cmd := Cmds["config"]
 	if len(args) <= 0 {
-		fmt.Fprintln(os.Stderr,
-			"Usage: bowery", Cmds["config"].Usage, "\n\n"+Cmds["config"].Short+"\n")
-		fmt.Fprintln(os.Stderr, "Keys:\n  host\n  redis\n  provider")
-		return 2
+		fmt.Fprintln(os.Stderr, "Usage: bowery "+cmd.Usage, "\n\n"+cmd.Short)
+		cmd.PrintSubcmds(os.Stderr)
+		return 2 // --help uses 2.
 	}
 
-	value := ""
-	key := args[0]
-	if key != "host" && key != "redis" && key != "provider" {
-		rollbar.Report(errors.ErrInvalidConfigKey)
-		return 1
+	log.Fprintln(os.Stderr, "red", errors.ErrInvalidConfigKey, args[0])
+	PrintSuggestions(os.Stderr, args[0], Names(cmd.Subcmds))
+	return 1
+}
+
+// addConfigKey adds a subcommand to config that sets the key. If valid is
+// given, values it returns false for are rejected.
+func addConfigKey(cmd *Cmd, key, short string, valid func(string) bool) {
+	cmd.Subcmds[key] = &Cmd{
+		Run: func(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
+			return configRun(keen, rollbar, key, valid, args...)
+		},
+		Usage: "config " + key + " [value]",
+		Short: short,
 	}
+}
+
+func configRun(keen *keen.Client, rollbar *rollbar.Client, key string, valid func(string) bool, args ...string) int {
+	var err error
+	value := ""
 
-	if len(args) < 2 {
+	if len(args) < 1 {
 		value, err = prompt.Basic("Value", false)
 		if err != nil {
 			rollbar.Report(err)
 			return 1
 		}
 	} else {
-		value = args[1]
+		value = args[0]
+	}
+
+	if value != "" && valid != nil && !valid(value) {
+		log.Fprintln(os.Stderr, "red", errors.Newf(errors.ErrInvalidConfigValueTmpl, value, key))
+		return 1
 	}
 
 	dev, err := db.GetDeveloper()
@@ -78,3 +118,16 @@ func configRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
 	keen.AddEvent("bowery config", map[string]*db.Developer{"user": dev})
 	return 0
 }
+
+// isNumber creates a validator for whole numbers of at least min.
+func isNumber(min int) func(string) bool {
+	return func(value string) bool {
+		n, err := strconv.Atoi(value)
+		return err == nil && n >= min
+	}
+}
+
+// isLogSource checks if a value is the name of a log source.
+func isLogSource(value string) bool {
+	return value == logs.SourceAuto || value == logs.SourceRedis || value == logs.SourceHTTP
+}
@@ -2,11 +2,11 @@
 package cmds
 
 import (
-	"bytes"
 	"io"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	mutex "sync"
 	"time"
@@ -16,13 +16,15 @@ import (
 	"github.com/Bowery/bowery/db"
 	"github.com/Bowery/bowery/delancey"
 	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/bowery/hooks"
+	"github.com/Bowery/bowery/logs"
 	"github.com/Bowery/bowery/rollbar"
+	"github.com/Bowery/bowery/ssh"
 	"github.com/Bowery/bowery/sync"
 	"github.com/Bowery/bowery/version"
 	"github.com/Bowery/gopackages/keen"
 	"github.com/Bowery/gopackages/log"
 	"github.com/Bowery/gopackages/schemas"
-	"github.com/garyburd/redigo/redis"
 )
 
 var (
@@ -62,6 +64,30 @@ func connectRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int
 	}
 	log.Println("magenta", "Hey there,", strings.Split(dev.Developer.Name, " ")[0]+
 		". Connecting you to Bowery now...")
+	hooks.Timeout = configSeconds(dev, "hooktimeout", hooks.Timeout)
+
+	// Get the apps services, this also changes to the apps root.
+	services, err := db.GetServices()
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+
+	output, err := openLog(dev)
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+	defer output.Close()
+
+	// Run the preconnect hooks, they may generate config the services need.
+	for name, config := range services.Data {
+		err = hooks.Run(name, config, hooks.PreConnect, output)
+		if err != nil {
+			rollbar.Report(err)
+			return 1
+		}
+	}
 
 	state, err := updateOrCreateApp(dev)
 	if err != nil {
@@ -70,7 +96,14 @@ func connectRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int
 	}
 	defer api.Disconnect(dev.Token)
 
-	syncer, servicesUploading, err := initiateSync(state)
+	// Get the developers ssh key, creating one if needed.
+	key, err := ssh.CreateKey()
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+
+	syncer, servicesUploading, err := initiateSync(state, ssh.AuthorizedKey(key), configSeconds(dev, "timeout", 2*time.Minute), output)
 	if err != nil {
 		rollbar.Report(err)
 		return 1
@@ -103,13 +136,8 @@ func connectRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int
 		}
 	}()
 
-	logChan := make(chan redis.Conn, 1)
-	logFile, err := tailLogs(state, logChan)
-	if err != nil {
-		rollbar.Report(err)
-		return 1
-	}
-	defer logFile.Close()
+	logChan := make(chan logs.Source, 1)
+	tailLogs(output, dev, state, logChan)
 
 	go func() {
 		<-signals
@@ -156,8 +184,8 @@ func connectRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int
 						done <- 0
 					}
 				}
-			case conn := <-logChan:
-				defer conn.Close()
+			case source := <-logChan:
+				defer source.Close()
 			case ev := <-syncer.Event:
 				if connected {
 					log.Println("", ev)
@@ -222,9 +250,12 @@ func updateOrCreateApp(dev *db.Developer) (*db.State, error) {
 	return state, nil
 }
 
-func initiateSync(state *db.State) (*sync.Syncer, []*schemas.Service, error) {
+func initiateSync(state *db.State, key []byte, timeout time.Duration, output io.Writer) (*sync.Syncer, []*schemas.Service, error) {
 	syncer := sync.NewSyncer()
 	services := make([]*schemas.Service, 0)
+	syncer.Hook = func(service *schemas.Service, event string) {
+		runHook(service.Name, state.Config[service.Name], event, output)
+	}
 
 	log.Debug("Intiating file sync.")
 	log.Println("", "Services are availble in the forms:")
@@ -234,21 +265,17 @@ func initiateSync(state *db.State) (*sync.Syncer, []*schemas.Service, error) {
 
 	printService := func(service *schemas.Service) {
 		log.Println("magenta", "Service", service.Name, "is available at:")
-		appIdentifier := state.App.ID
-		if state.App.Name != "" {
-			appIdentifier = state.App.Name
-		}
 
-		url := "80: " + service.PublicAddr
-		if ENV != "development" {
-			url = service.Name + "." + appIdentifier + ".boweryapps.com"
+		url := serviceAddr(state, service, "")
+		if ENV == "development" {
+			url = "80: " + url
 		}
 		log.Println("magenta", " ", url)
 
-		for port, addr := range service.CustomPorts {
-			url = port + ": " + addr
-			if ENV != "development" {
-				url = port + "." + service.Name + "." + appIdentifier + ".boweryapps.com"
+		for port := range service.CustomPorts {
+			url = serviceAddr(state, service, port)
+			if ENV == "development" {
+				url = port + ": " + url
 			}
 
 			log.Println("magenta", " ", url)
@@ -279,26 +306,24 @@ func initiateSync(state *db.State) (*sync.Syncer, []*schemas.Service, error) {
 		services = append(services, service)
 	}
 
-	// Check if the services are running and upload/sync them.
+	// Wait for the satellites to be ready.
+	err := waitForServices(services, timeout)
+	if err != nil {
+		syncer.Close()
+		return nil, nil, err
+	}
+
+	// Upload/sync the services.
 	for _, service := range services {
 		config := state.Config[service.Name]
 		log.Debug("Starting upload for", service.Name, "possibly syncing to", service.SatelliteAddr)
 
-		// Ensure satellite is running for the service.
-		var err error
-		i := 0
-		for i < 1000 {
-			err = delancey.CheckHealth(service.SatelliteAddr)
-			if err == nil {
-				break
-			}
-
-			i++
-			<-time.After(8 * time.Millisecond)
-		}
+		// Add the developers key so ssh works, syncing doesn't depend on it.
+		err = delancey.AddKey(service.SatelliteAddr, key)
 		if err != nil {
-			syncer.Close()
-			return nil, nil, errors.NewStackError(errors.ErrContainerConnect)
+			log.Debug("Adding ssh key to", service.Name, "failed:", err)
+			log.Println("yellow", "Unable to add your ssh key to", service.Name+".",
+				"Reconnect if `bowery ssh` doesn't work.")
 		}
 
 		syncer.Watch(strings.Split(config.Path, ":")[0], service)
@@ -313,80 +338,164 @@ func initiateSync(state *db.State) (*sync.Syncer, []*schemas.Service, error) {
 	return syncer, services, nil
 }
 
-func tailLogs(state *db.State, logChan chan redis.Conn) (*syncWriter, error) {
-	log.Debug("Tailing logs.")
+// serviceAddr gets the public address for a service, or for one of its
+// custom ports if port is given.
+func serviceAddr(state *db.State, service *schemas.Service, port string) string {
+	if ENV == "development" {
+		if port != "" {
+			return service.CustomPorts[port]
+		}
+
+		return service.PublicAddr
+	}
+
+	appIdentifier := state.App.ID
+	if state.App.Name != "" {
+		appIdentifier = state.App.Name
+	}
+
+	addr := service.Name + "." + appIdentifier + ".boweryapps.com"
+	if port != "" {
+		addr = port + "." + addr
+	}
+
+	return addr
+}
+
+// waitForServices waits for the services satellites to be ready in
+// parallel, printing progress for the ones that are slow to boot.
+func waitForServices(services []*schemas.Service, timeout time.Duration) error {
+	var wg mutex.WaitGroup
+	errs := make(chan error, len(services))
+
+	for _, service := range services {
+		wg.Add(1)
+		go func(service *schemas.Service) {
+			defer wg.Done()
+			var reported time.Duration
+
+			err := delancey.WaitHealthy(service.SatelliteAddr, timeout, func(waited time.Duration, err error) {
+				log.Debug("Health check for", service.Name, "failed:", err)
+
+				// Report the first failure after a second, then every 10 seconds.
+				if waited < time.Second || (reported > 0 && waited-reported < 10*time.Second) {
+					return
+				}
+				reported = waited
 
-	file, err := os.OpenFile(filepath.Join(".bowery", "output.log"),
-		os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+				log.Println("yellow", "Waiting for", service.Name, "to start...",
+					"("+strconv.Itoa(int(waited/time.Second))+"s)")
+			})
+			if err != nil {
+				errs <- errors.Newf(errors.ErrNotReadyTmpl, service.Name, timeout)
+				return
+			}
+
+			if reported > 0 {
+				log.Println("cyan", "Service", service.Name, "is ready.")
+			}
+		}(service)
+	}
+
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
+
+// configSeconds gets a duration in seconds from the developers config, or
+// def if it isn't set.
+func configSeconds(dev *db.Developer, key string, def time.Duration) time.Duration {
+	seconds, err := strconv.Atoi(dev.Config[key])
+	if err != nil || seconds <= 0 {
+		return def
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// openLog opens .bowery/output.log with the rotation settings from the
+// developers config.
+func openLog(dev *db.Developer) (*logs.Writer, error) {
+	// Get the rotation settings, the size is in MB.
+	maxSize := logs.DefaultMaxSize
+	maxFiles := logs.DefaultMaxFiles
+	if dev.Config != nil {
+		size, err := strconv.Atoi(dev.Config["logsize"])
+		if err == nil && size > 0 {
+			maxSize = int64(size) * 1024 * 1024
+		}
+
+		files, err := strconv.Atoi(dev.Config["logfiles"])
+		if err == nil && files >= 0 {
+			maxFiles = files
+		}
+	}
+
+	return logs.NewWriter(filepath.Join(".bowery", "output.log"), maxSize, maxFiles)
+}
+
+// runHook runs a services hook for the event, failures are only warned
+// about since the service is already running.
+func runHook(name string, config *db.Service, event string, output io.Writer) {
+	log.Debug("Running", event, "hook for", name)
+
+	err := hooks.Run(name, config, event, output)
 	if err != nil {
-		return nil, err
+		log.Println("yellow", err)
 	}
-	output := &syncWriter{File: file}
+}
+
+func tailLogs(output io.Writer, dev *db.Developer, state *db.State, logChan chan logs.Source) {
+	log.Debug("Tailing logs.")
 
 	// Connect and write logs to file, ignore errors because syncing
 	// shouldn't depend on logs.
 	go func() {
 		var (
-			conn redis.Conn
-			err  error
+			source logs.Source
+			err    error
 		)
-		i := 0
+		deadline := time.Now().Add(configSeconds(dev, "timeout", 2*time.Minute))
 
-		// Attempt to connect.
-		for i < 1000 {
-			conn, err = redis.Dial("tcp", api.RedisPath)
+		// Attempt to connect until the timeout, dialing may take a while itself.
+		for {
+			source, err = logs.Open(dev.Config["logsource"], state.App.ID, dev.Token)
 			if err == nil {
-				logChan <- conn
+				logChan <- source
+				break
+			}
+			if time.Now().After(deadline) {
 				break
 			}
 
-			i++
 			<-time.After(time.Millisecond * 50)
 		}
 
 		// No successful connection so just forget it.
-		if conn == nil {
-			log.Debug("Couldn't connect to Redis", api.RedisPath)
-			return
-		}
-		pubsub := redis.PubSubConn{Conn: conn}
-		log.Debug("Connected to Redis", api.RedisPath)
-
-		write := func(data []byte) error {
-			buf := bytes.NewBuffer(data)
-
-			_, err := io.Copy(output, buf)
-			if err != nil {
-				err = errors.NewStackError(err)
-			}
-
-			return err
-		}
-
-		err = pubsub.Subscribe("logs:" + state.App.ID)
 		if err != nil {
+			log.Debug("Couldn't connect to a log source", err)
+			log.Println("yellow", "Unable to stream logs, output won't be saved until you reconnect:", err)
 			return
 		}
+		log.Debug("Connected to log source")
 
 		for {
-			switch res := pubsub.Receive().(type) {
-			case redis.Message:
-				err = write(res.Data)
+			select {
+			case record := <-source.Records():
+				data, err := record.Marshal()
 				if err != nil {
 					return
 				}
-			case redis.PMessage:
-				err = write(res.Data)
+
+				_, err = output.Write(append(data, '\n'))
 				if err != nil {
 					return
 				}
-			case error:
+			case <-source.Errors():
 				return
 			}
 		}
 	}()
-
-	return output, nil
 }
 
 func apiStatus(token string) {
@@ -406,30 +515,3 @@ func apiStatus(token string) {
 		}
 	}
 }
-
-// syncWriter syncs to the fs after writes.
-type syncWriter struct {
-	File  *os.File
-	mutex mutex.Mutex
-}
-
-// Write writes the given buffer and syncs to the fs.
-func (sw *syncWriter) Write(b []byte) (int, error) {
-	sw.mutex.Lock()
-	defer sw.mutex.Unlock()
-
-	n, err := sw.File.Write(b)
-	if err != nil {
-		return n, err
-	}
-
-	return n, sw.File.Sync()
-}
-
-// Close closes the writer after any writes have completed.
-func (sw *syncWriter) Close() error {
-	sw.mutex.Lock()
-	defer sw.mutex.Unlock()
-
-	return sw.File.Close()
-}
@@ -0,0 +1,110 @@
+// Copyright 2013-2014 Bowery, Inc.
+package cmds
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Bowery/bowery/db"
+	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/bowery/rollbar"
+	"github.com/Bowery/bowery/ssh"
+	"github.com/Bowery/gopackages/keen"
+	"github.com/Bowery/gopackages/log"
+	"github.com/Bowery/gopackages/schemas"
+)
+
+func init() {
+	cmd := &Cmd{
+		Run:   cpRun,
+		Usage: "cp <src> <dest>",
+		Short: "Copy files between a service and your machine.",
+	}
+	cmd.Description = "Copies files between a service and your machine, directories are copied\n" +
+		"recursively. Paths on a service are prefixed with the service name, e.g.\n\n" +
+		"  $ bowery cp web:/application/logs ./logs\n" +
+		"  $ bowery cp ./fixtures web:/application/fixtures"
+
+	Cmds["cp"] = cmd
+}
+
+func cpRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr,
+			"Usage: bowery "+Cmds["cp"].Usage, "\n\n"+Cmds["cp"].Short)
+		return 2 // --help uses 2.
+	}
+
+	state, err := db.GetState()
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+
+	// Find which argument refers to a service, only one may.
+	services := make([]string, len(state.App.Services))
+	for i, v := range state.App.Services {
+		services[i] = v.Name
+	}
+	download := true
+	service, remote := servicePath(state.App.Services, args[0])
+	dest, destRemote := servicePath(state.App.Services, args[1])
+	if service != nil && dest != nil {
+		log.Fprintln(os.Stderr, "red", errors.ErrCopyServices)
+		return 1
+	}
+	if dest != nil {
+		service, remote = dest, destRemote
+		download = false
+	}
+
+	// Handle no service found.
+	if service == nil {
+		log.Fprintln(os.Stderr, "red", errors.ErrInvalidService, args[0], args[1])
+		log.Println("yellow", "Valid services:", strings.Join(services, ", "))
+		log.Println("yellow", "Prefix the path on the service with its name, e.g. web:/application")
+		return 1
+	}
+	if remote == "" {
+		remote = "."
+	}
+
+	copied := 0
+	progress := func(name string) {
+		copied++
+		log.Println("", "("+service.Name+"): Copied", name)
+	}
+
+	log.Println("cyan", "Copying", args[0], "to", args[1]+".")
+	if download {
+		err = ssh.Download(service, remote, args[1], progress)
+	} else {
+		err = ssh.Upload(service, args[0], remote, progress)
+	}
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+	log.Println("magenta", "Copied", copied, "file(s).")
+
+	keen.AddEvent("bowery cp", map[string]interface{}{
+		"name":     service.Name,
+		"download": download,
+		"files":    copied,
+		"appId":    state.App.ID,
+	})
+	return 0
+}
+
+// servicePath gets the service a path is prefixed with, and the path on it.
+// The first service matching is used.
+func servicePath(services []*schemas.Service, arg string) (*schemas.Service, string) {
+	for _, service := range services {
+		if strings.HasPrefix(arg, service.Name+":") {
+			return service, strings.TrimPrefix(arg, service.Name+":")
+		}
+	}
+
+	return nil, ""
+}
@@ -16,9 +16,10 @@ import (
 
 func init() {
 	Cmds["destroy"] = &Cmd{
-		Run:   destroyRun,
-		Usage: "destroy <id or name>",
-		Short: "Destroy an application and its services.",
+		Run:      destroyRun,
+		Usage:    "destroy <id or name>",
+		Short:    "Destroy an application and its services.",
+		Complete: completeApps,
 	}
 }
 
@@ -16,13 +16,34 @@ import (
 
 func init() {
 	Cmds["errors"] = &Cmd{
-		Run:   errorsRun,
-		Usage: "errors [id]",
-		Short: "Get information on an error.",
+		Run:      errorsRun,
+		Usage:    "errors [id]",
+		Short:    "Get information on an error.",
+		Complete: completeErrors,
+	}
+}
+
+// errorInfo is an error printed by `bowery --json errors`.
+type errorInfo struct {
+	Code        string `json:"code"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// newErrorInfo creates the info for an error, without the code in the title.
+func newErrorInfo(err errors.Error) *errorInfo {
+	return &errorInfo{
+		Code:        err.Code,
+		Title:       strings.TrimSpace(strings.Split(err.Title, "Error Code")[0]),
+		Description: err.Description,
 	}
 }
 
 func errorsRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
+	if Cmds["errors"].JSON {
+		return errorsJSON(rollbar, args...)
+	}
+
 	if len(args) <= 0 {
 		log.Println("cyan", "All Errors. Run `bowery errors <code>` for more info.\n")
 		tabWriter := tabwriter.NewWriter(os.Stderr, 0, 0, 1, ' ', 0)
@@ -57,3 +78,38 @@ func errorsRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
 
 	return 0
 }
+
+// errorsJSON prints the error with the code in args, or all the errors, as
+// JSON.
+func errorsJSON(rollbar *rollbar.Client, args ...string) int {
+	var output interface{}
+
+	if len(args) <= 0 {
+		infos := make([]*errorInfo, 0)
+		for _, err := range errors.GetAll() {
+			infos = append(infos, newErrorInfo(err))
+		}
+		output = infos
+	} else {
+		index, err := strconv.Atoi(args[0])
+		if err != nil {
+			log.Fprintln(os.Stderr, "yellow", "Input must be an integer.")
+			return 1
+		}
+
+		info, err := errors.Get(index)
+		if err != nil {
+			log.Fprintln(os.Stderr, "yellow", err)
+			return 1
+		}
+		output = newErrorInfo(info)
+	}
+
+	err := writeJSON(os.Stdout, output)
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,81 @@
+// Copyright 2013-2014 Bowery, Inc.
+package cmds
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Bowery/bowery/db"
+	"github.com/Bowery/bowery/rollbar"
+	"github.com/Bowery/bowery/ssh"
+	"github.com/Bowery/gopackages/keen"
+	"github.com/Bowery/gopackages/log"
+	"github.com/Bowery/gopackages/schemas"
+)
+
+func init() {
+	cmd := &Cmd{
+		Run:        execRun,
+		Usage:      "exec [-t] <name> -- <command>",
+		Short:      "Run a single command on a service.",
+		Flags:      flag.NewFlagSet("exec", flag.ContinueOnError),
+		FlagsFirst: true,
+	}
+	cmd.Description = "Runs a single command on a service via ssh, with stdin, stdout and\n" +
+		"stderr piped. The exit status is the exit status of the command. The\n" +
+		"arguments are given to the command as is, for shell syntax run a shell,\n" +
+		"e.g. `bowery exec web -- sh -c 'cd /application && make'`."
+	cmd.Flags.Bool("t", false, "Allocate a tty for the command.")
+
+	Cmds["exec"] = cmd
+}
+
+func execRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
+	// Separate the name from the command, "--" is optional.
+	if len(args) > 1 && args[1] == "--" {
+		args = append(args[:1], args[2:]...)
+	}
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr,
+			"Usage: bowery "+Cmds["exec"].Usage, "\n\n"+Cmds["exec"].Short)
+		return 2 // --help uses 2.
+	}
+
+	state, err := db.GetState()
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+
+	// Create slices of service names, and find the requested service.
+	var service *schemas.Service
+	services := make([]string, len(state.App.Services))
+	for i, v := range state.App.Services {
+		services[i] = v.Name
+		if args[0] == v.Name {
+			service = v
+		}
+	}
+
+	// Handle no service found.
+	if service == nil {
+		printInvalidService(args[0], services)
+		return 1
+	}
+	command := ssh.QuoteArgs(args[1:])
+	log.Debug("Found service", service.Name, "ssh addr:", service.SSHAddr, "running:", command)
+
+	keen.AddEvent("bowery exec", map[string]string{
+		"name":  service.Name,
+		"appId": state.App.ID,
+	})
+
+	status, err := ssh.Exec(service, command, Cmds["exec"].flagBool("t"))
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+
+	return status
+}
@@ -0,0 +1,111 @@
+// Copyright 2013-2014 Bowery, Inc.
+package cmds
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/Bowery/bowery/db"
+	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/bowery/rollbar"
+	"github.com/Bowery/bowery/ssh"
+	"github.com/Bowery/gopackages/keen"
+	"github.com/Bowery/gopackages/log"
+	"github.com/Bowery/gopackages/schemas"
+)
+
+func init() {
+	cmd := &Cmd{
+		Run:   forwardRun,
+		Usage: "forward <name> <local>:<remote> [...]",
+		Short: "Forward local ports to a service.",
+	}
+	cmd.Description = "Forwards local ports to ports on a service through its ssh connection,\n" +
+		"e.g. `bowery forward db 5432` or `bowery forward db 15432:5432`. The\n" +
+		"connection is re-established if the service restarts."
+
+	Cmds["forward"] = cmd
+}
+
+func forwardRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr,
+			"Usage: bowery "+Cmds["forward"].Usage, "\n\n"+Cmds["forward"].Short)
+		return 2 // --help uses 2.
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, os.Kill)
+	defer signal.Stop(signals)
+
+	ports := make([]*ssh.PortMap, len(args)-1)
+	for i, arg := range args[1:] {
+		port, err := ssh.ParsePortMap(arg)
+		if err != nil {
+			log.Fprintln(os.Stderr, "red", err)
+			return 1
+		}
+
+		ports[i] = port
+	}
+
+	state, err := db.GetState()
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+
+	// Create slices of service names, and find the requested service.
+	var service *schemas.Service
+	services := make([]string, len(state.App.Services))
+	for i, v := range state.App.Services {
+		services[i] = v.Name
+		if args[0] == v.Name {
+			service = v
+		}
+	}
+
+	// Handle no service found.
+	if service == nil {
+		printInvalidService(args[0], services)
+		return 1
+	}
+	log.Debug("Found service", service.Name, "ssh addr:", service.SSHAddr)
+
+	forwarder := ssh.NewForwarder(service, ports)
+	forwarder.Lookup = func() (*schemas.Service, error) {
+		state, err := db.GetState()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range state.App.Services {
+			if v.Name == service.Name {
+				return v, nil
+			}
+		}
+
+		return nil, errors.ErrInvalidService
+	}
+
+	err = forwarder.Start()
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+	defer forwarder.Close()
+
+	for _, port := range ports {
+		log.Println("magenta", "Forwarding localhost:"+port.Local, "to", service.Name+":"+port.Remote)
+	}
+	log.Println("magenta", "Press CTRL+C to stop forwarding.")
+
+	keen.AddEvent("bowery forward", map[string]interface{}{
+		"name":  service.Name,
+		"ports": len(ports),
+		"appId": state.App.ID,
+	})
+
+	<-signals
+	return 0
+}
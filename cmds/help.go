@@ -4,6 +4,7 @@ package cmds
 import (
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/Bowery/bowery/errors"
@@ -21,29 +22,49 @@ func init() {
 }
 
 func helpRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
+	AddPlugins()
+
 	if len(args) > 0 {
 		cmd, ok := Cmds[args[0]]
 		if !ok {
 			log.Fprintln(os.Stderr, "red", errors.ErrInvalidCommand, args[0])
+			PrintSuggestions(os.Stderr, args[0], Names(Cmds))
 			return 1
 		}
 
+		// Find the subcommand, e.g. `bowery help settings password`.
+		for _, name := range args[1:] {
+			subcmd, ok := cmd.Subcmds[name]
+			if !ok {
+				log.Fprintln(os.Stderr, "red", errors.ErrInvalidCommand, strings.Join(args, " "))
+				PrintSuggestions(os.Stderr, name, Names(cmd.Subcmds))
+				return 1
+			}
+			cmd = subcmd
+		}
+
 		fmt.Fprintln(os.Stderr, "Usage: bowery", cmd.Usage+"\n")
 		if cmd.Description == "" {
 			cmd.Description = cmd.Short
 		}
 
 		fmt.Fprintln(os.Stderr, cmd.Description)
+		cmd.PrintSubcmds(os.Stderr)
+		cmd.PrintFlags(os.Stderr)
 		return 2 // --help uses 2.
 	}
 
 	// Ensure output is correctly aligned.
 	tabWriter := tabwriter.NewWriter(os.Stderr, 0, 0, 8, ' ', 0)
 	fmt.Fprintln(tabWriter, "Usage: bowery [option] <command> [args]\n")
-	fmt.Fprintln(tabWriter, "Options:\n  --force, -f\tForce actions instead of asking.\n")
+	fmt.Fprintln(tabWriter, "Options:\n  --force, -f\tForce actions instead of asking.")
+	fmt.Fprintln(tabWriter, "  --json\tPrint JSON to stdout, logs and status -watch print a document per line.")
+	fmt.Fprintln(tabWriter, "  --yes, --non-interactive\tAnswer yes to questions, and read input from BOWERY_<PROMPT> variables.\n")
 	fmt.Fprintln(tabWriter, "Commands:")
 
-	for _, cmd := range Cmds {
+	for _, name := range Names(Cmds) {
+		cmd := Cmds[name]
+
 		// \t is used to separate columns.
 		fmt.Fprintln(tabWriter, "  "+cmd.Usage+"\t"+cmd.Short)
 	}
@@ -3,6 +3,7 @@ package cmds
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/Bowery/bowery/db"
@@ -21,6 +22,37 @@ func init() {
 	}
 }
 
+// infoOutput is the developer and app info printed by `bowery --json info`,
+// Developer and App are null if not logged in or connected.
+type infoOutput struct {
+	Developer *developerInfo    `json:"developer"`
+	Config    map[string]string `json:"config"`
+	App       *appServicesInfo  `json:"app"`
+}
+
+type developerInfo struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+type appServicesInfo struct {
+	ID       string                  `json:"id"`
+	Name     string                  `json:"name"`
+	Services map[string]*serviceInfo `json:"services"`
+}
+
+type serviceInfo struct {
+	URL        string            `json:"url"`
+	Image      string            `json:"image"`
+	Path       string            `json:"path,omitempty"`
+	RemotePath string            `json:"remotePath,omitempty"`
+	Ports      []interface{}     `json:"ports,omitempty"`
+	Build      string            `json:"build,omitempty"`
+	Test       string            `json:"test,omitempty"`
+	Start      string            `json:"start,omitempty"`
+	Env        map[string]string `json:"env,omitempty"`
+}
+
 func infoRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
 	dev, devErr := db.GetDeveloper()
 	if devErr != nil && devErr != errors.ErrNoDeveloper {
@@ -34,6 +66,61 @@ func infoRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
 		return 1
 	}
 
+	keen.AddEvent("bowery info", map[string]interface{}{
+		"user": dev,
+		"app":  state,
+	})
+
+	if Cmds["info"].JSON {
+		output := &infoOutput{Config: make(map[string]string)}
+		if devErr == nil {
+			output.Developer = &developerInfo{Email: dev.Developer.Email, Name: dev.Developer.Name}
+		}
+		for k, v := range dev.Config {
+			if v != "" {
+				output.Config[k] = v
+			}
+		}
+
+		if err != errors.ErrNotConnected {
+			output.App = &appServicesInfo{
+				ID:       state.App.ID,
+				Name:     state.App.Name,
+				Services: make(map[string]*serviceInfo),
+			}
+
+			for name, service := range state.Config {
+				info := &serviceInfo{
+					URL:   serviceURL(state, name),
+					Image: service.Image,
+					Ports: service.Ports,
+					Build: service.Build,
+					Test:  service.Test,
+					Start: service.Start,
+					Env:   service.Env,
+				}
+				if service.Path != "" {
+					paths := strings.Split(service.Path, ":")
+
+					info.Path = paths[0]
+					if len(paths) > 1 {
+						info.RemotePath = paths[1]
+					}
+				}
+
+				output.App.Services[name] = info
+			}
+		}
+
+		err = writeJSON(os.Stdout, output)
+		if err != nil {
+			rollbar.Report(err)
+			return 1
+		}
+
+		return 0
+	}
+
 	if ENV == "development" {
 		log.Println("magenta", "Developer(development mode):")
 	} else {
@@ -67,25 +154,8 @@ func infoRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
 		log.Println("", "  ID:", state.App.ID)
 
 		for name, service := range state.Config {
-			var apiService *schemas.Service
-			for _, serv := range state.App.Services {
-				if serv.Name == name {
-					apiService = serv
-					break
-				}
-			}
-
-			url := apiService.PublicAddr
-			if ENV != "development" {
-				if state.App.Name != "" {
-					url = "http://" + name + "." + state.App.Name + ".boweryapps.com"
-				} else {
-					url = "http://" + name + "." + state.App.ID + ".boweryapps.com"
-				}
-			}
-
 			log.Println("", "  "+name+":")
-			log.Println("", "    URL:", url)
+			log.Println("", "    URL:", serviceURL(state, name))
 			log.Println("", "    Image:", service.Image)
 			if service.Path != "" {
 				paths := strings.Split(service.Path, ":")
@@ -116,9 +186,23 @@ func infoRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
 		}
 	}
 
-	keen.AddEvent("bowery info", map[string]interface{}{
-		"user": dev,
-		"app":  state,
-	})
 	return 0
 }
+
+// serviceURL gets the public url for a service in the app.
+func serviceURL(state *db.State, name string) string {
+	service := &schemas.Service{Name: name}
+	for _, v := range state.App.Services {
+		if v.Name == name {
+			service = v
+			break
+		}
+	}
+
+	addr := serviceAddr(state, service, "")
+	if ENV == "development" || addr == "" {
+		return addr
+	}
+
+	return "http://" + addr
+}
@@ -120,22 +120,12 @@ func getToken(dev *db.Developer) error {
 
 	// Get email and password up to 5 times, then report the error.
 	for token == "" && i < 5 {
-		validEmail := false
 		email := ""
 		pass := ""
 
-		for !validEmail {
-			email, err = prompt.Basic("Email", true)
-			if err != nil {
-				return err
-			}
-
-			_, err = mail.ParseAddress(email)
-			if err == nil {
-				validEmail = true
-			} else {
-				log.Println("yellow", "Try again! Valid email address required.")
-			}
+		email, err = promptEmail()
+		if err != nil {
+			return err
 		}
 
 		pass, err = prompt.Password("Password")
@@ -146,6 +136,11 @@ func getToken(dev *db.Developer) error {
 
 		token, err = broome.GetTokenByLogin(email, pass)
 		if err != nil {
+			// Retrying would use the same answers.
+			if prompt.NonInteractive {
+				return err
+			}
+
 			if i < 4 {
 				log.Fprintln(os.Stderr, "red", errors.Newf(errors.ErrLoginRetryTmpl, err))
 			}
@@ -179,3 +174,21 @@ func updateDeveloper(dev *db.Developer) error {
 	dev.Developer = developer
 	return dev.Save()
 }
+
+// promptEmail gets a valid email address.
+func promptEmail() (string, error) {
+	return prompt.Custom("Email", func(input string) (string, bool) {
+		if input == "" {
+			log.Fprintln(os.Stderr, "red", "Email", errors.ErrEmpty)
+			return "", false
+		}
+
+		_, err := mail.ParseAddress(input)
+		if err != nil {
+			log.Println("yellow", "Try again! Valid email address required.")
+			return "", false
+		}
+
+		return input, true
+	})
+}
@@ -2,32 +2,87 @@
 package cmds
 
 import (
+	"bytes"
+	"flag"
+	"hash/fnv"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
 
 	"github.com/Bowery/bowery/db"
 	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/bowery/logs"
 	"github.com/Bowery/bowery/rollbar"
+	"github.com/Bowery/bowery/version"
 	"github.com/Bowery/gopackages/keen"
 	"github.com/Bowery/gopackages/log"
-
-	"github.com/ActiveState/tail"
 )
 
+// serviceColors are the colors used to prefix service output.
+var serviceColors = []string{"cyan", "magenta", "green", "yellow", "blue"}
+
 func init() {
-	Cmds["logs"] = &Cmd{
+	cmd := &Cmd{
 		Run:   logsRun,
-		Usage: "logs",
-		Short: "Tail your application's logs."}
+		Usage: "logs [names] [-since time] [-until time] [-grep pattern] [-json] [-export file]",
+		Short: "Tail your application's logs.",
+		Flags: flag.NewFlagSet("logs", flag.ContinueOnError),
+	}
+	cmd.Description = "Tails the output of your application's services, or only the named\n" +
+		"services. Each line is prefixed with the name of the service it came from.\n" +
+		"Output saved while `bowery connect` was running, including rotated logs, is\n" +
+		"shown first. While connect is running output.log is followed across\n" +
+		"rotations, otherwise output is streamed from Bowery. Times are either a\n" +
+		"duration like 10m or a time like 2014-06-01T15:04:05Z."
+	cmd.Flags.String("since", "", "Only show saved output after a time.")
+	cmd.Flags.String("until", "", "Only show saved output before a time, output isn't tailed.")
+	cmd.Flags.String("grep", "", "Only show lines matching a regular expression.")
+	cmd.Flags.Bool("json", false, "Print each line as a JSON record, e.g. for jq.")
+	cmd.Flags.String("export", "", "Write the saved output and the app's state, "+
+		"with secrets redacted, to a tar.gz file for bug reports.")
+
+	Cmds["logs"] = cmd
 }
 
 func logsRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
 	// Create and register signals.
 	signals := make(chan os.Signal, 1)
-	done := make(chan int, 1)
 	signal.Notify(signals, os.Interrupt, os.Kill)
 	defer signal.Stop(signals)
+	cmd := Cmds["logs"]
+	names := args
+
+	since, err := parseSince(cmd.flagString("since"), time.Now())
+	if err != nil {
+		log.Fprintln(os.Stderr, "red", err)
+		return 1
+	}
+
+	until, err := parseSince(cmd.flagString("until"), time.Now())
+	if err != nil {
+		log.Fprintln(os.Stderr, "red", err)
+		return 1
+	}
+
+	grep, err := regexp.Compile(cmd.flagString("grep"))
+	if err != nil {
+		log.Fprintln(os.Stderr, "red", err)
+		return 1
+	}
+
+	// Get the export path before GetState changes to the app's root.
+	exportPath := ""
+	if cmd.flagString("export") != "" {
+		exportPath, err = filepath.Abs(cmd.flagString("export"))
+		if err != nil {
+			rollbar.Report(errors.NewStackError(err))
+			return 1
+		}
+	}
 
 	dev, err := db.GetDeveloper()
 	if err != nil {
@@ -35,44 +90,243 @@ func logsRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
 		return 1
 	}
 
+	state, err := db.GetState()
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+
+	// Make sure the names are services, and get the width for prefixes.
+	services := make([]string, len(state.App.Services))
+	width := 0
+	for i, v := range state.App.Services {
+		services[i] = v.Name
+		if len(v.Name) > width {
+			width = len(v.Name)
+		}
+	}
+	for _, name := range names {
+		found := false
+		for _, service := range services {
+			if name == service {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			printInvalidService(name, services)
+			return 1
+		}
+	}
+
 	keen.AddEvent("bowery logs", map[string]*db.Developer{"user": dev})
 
-	db.GetState() // Do this to ensure we're at the root of the app.
-	tailer, err := tail.TailFile(filepath.Join(".bowery", "output.log"), tail.Config{
-		MustExist: true,
-		Follow:    true,
-		Logger:    tail.DiscardingLogger,
-	})
-	if err != nil {
-		if !os.IsNotExist(err) {
-			err = errors.NewStackError(err)
-		} else {
-			err = errors.ErrNotConnected
+	// match checks if a record should be shown.
+	match := func(record *logs.Record) bool {
+		if len(names) > 0 {
+			found := false
+			for _, name := range names {
+				if record.Service == name {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				return false
+			}
+		}
+
+		return grep.MatchString(record.Message)
+	}
+
+	// output prints a record, or saves it if exporting. On error the command
+	// should finish.
+	var export bytes.Buffer
+	output := func(record *logs.Record) error {
+		if exportPath != "" {
+			data, err := record.Marshal()
+			if err != nil {
+				return err
+			}
+
+			export.Write(append(data, '\n'))
+			return nil
 		}
 
+		if !cmd.flagBool("json") && !cmd.JSON {
+			printRecord(record, width)
+			return nil
+		}
+
+		data, err := record.Marshal()
+		if err != nil {
+			return err
+		}
+
+		_, err = os.Stdout.Write(append(data, '\n'))
+		if err != nil {
+			return errors.NewStackError(err)
+		}
+
+		return nil
+	}
+
+	// Print the saved output first, oldest first including rotated logs. It
+	// only exists if connect has ran. When tailing while connect is writing
+	// output.log, it's followed instead so lines written while reading aren't
+	// missed.
+	path := filepath.Join(".bowery", "output.log")
+	tailing := exportPath == "" && until.IsZero()
+	follow := tailing && logs.Writing(path)
+	files, err := logs.Files(path)
+	if err != nil {
 		rollbar.Report(err)
 		return 1
 	}
-	defer tailer.Stop()
-
-	// Catch signals and finish.
-	go func() {
-		<-signals
-		done <- 0
-	}()
-
-	// Print lines as they come in, on error finish.
-	go func() {
-		for line := range tailer.Lines {
-			if line.Err != nil {
-				rollbar.Report(errors.NewStackError(line.Err))
-				done <- 1
-				return
+	for _, file := range files {
+		if follow && file == path {
+			continue
+		}
+
+		err = logs.ReadRecords(file, func(record *logs.Record) error {
+			if record.Time.Before(since) || (!until.IsZero() && record.Time.After(until)) ||
+				!match(record) {
+				return nil
+			}
+
+			return output(record)
+		})
+		if err != nil {
+			rollbar.Report(err)
+			return 1
+		}
+	}
+
+	if exportPath != "" {
+		return exportLogs(rollbar, state, exportPath, export.Bytes())
+	}
+	if !tailing {
+		return 0
+	}
+
+	// Follow output.log across rotations while connect writes it, otherwise
+	// stream from the logs service.
+	var source logs.Source
+	if follow {
+		var tail *logs.Tail
+		tail, err = logs.Follow(path)
+		if err == nil {
+			source = tail
+		}
+	}
+	if source == nil {
+		source, err = logs.Open(dev.Config["logsource"], state.App.ID, dev.Token)
+		if err != nil {
+			rollbar.Report(err)
+			return 1
+		}
+	}
+	defer source.Close()
+
+	// Print records as they come in, on error finish.
+	for {
+		select {
+		case record := <-source.Records():
+			if record.Time.Before(since) || !match(record) {
+				continue
+			}
+
+			if err = output(record); err != nil {
+				rollbar.Report(err)
+				return 1
 			}
+		case err = <-source.Errors():
+			rollbar.Report(err)
+			return 1
+		case <-signals:
+			return 0
+		}
+	}
+}
+
+// exportLogs writes the exported records and the apps state to a tar.gz
+// file at path.
+func exportLogs(rollbar *rollbar.Client, state *db.State, path string, records []byte) int {
+	stateData, err := logs.Redact(state)
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+
+	info := "Version: " + version.Version + "\n" +
+		"OS: " + runtime.GOOS + "/" + runtime.GOARCH + "\n" +
+		"Exported: " + time.Now().Format(time.RFC3339) + "\n"
 
-			log.Println("", line.Text)
+	err = logs.Export(path, map[string][]byte{
+		"output.log": records,
+		"state.json": stateData,
+		"info.txt":   []byte(info),
+	})
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+
+	log.Println("magenta", "Exported logs to", path+".")
+	return 0
+}
+
+// parseSince parses a duration before now, or an RFC 3339 time. An empty
+// string gives the zero time.
+func parseSince(since string, now time.Time) (time.Time, error) {
+	if since == "" {
+		return time.Time{}, nil
+	}
+
+	duration, err := time.ParseDuration(since)
+	if err == nil {
+		return now.Add(-duration), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return time.Time{}, errors.ErrInvalidSince
+	}
+
+	return t, nil
+}
+
+// printRecord prints a record with its time, and its service name padded to
+// width. Output from stderr is printed in red.
+func printRecord(record *logs.Record, width int) {
+	if !record.Time.IsZero() {
+		log.Print("", record.Time.Local().Format("15:04:05")+" ")
+	}
+
+	if record.Service != "" {
+		prefix := record.Service
+		if len(prefix) < width {
+			prefix += strings.Repeat(" ", width-len(prefix))
 		}
-	}()
 
-	return <-done
+		log.Print(serviceColor(record.Service), prefix+" | ")
+	}
+
+	color := ""
+	if record.Stream == logs.Stderr {
+		color = "red"
+	}
+
+	log.Println(color, record.Message)
+}
+
+// serviceColor gets the color for a service, the same service always gets
+// the same color.
+func serviceColor(name string) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(name))
+
+	return serviceColors[hash.Sum32()%uint32(len(serviceColors))]
 }
@@ -0,0 +1,208 @@
+// Copyright 2013-2014 Bowery, Inc.
+package cmds
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"github.com/Bowery/bowery/api"
+	"github.com/Bowery/bowery/db"
+	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/bowery/rollbar"
+	"github.com/Bowery/gopackages/keen"
+	"github.com/Bowery/gopackages/log"
+)
+
+// PluginPrefix is the prefix for plugin executables, `bowery <name>` runs
+// bowery-<name> if there's no command with the name.
+const PluginPrefix = "bowery-"
+
+// LookupCmd gets the command with the name. If there's none the plugin for
+// the name is looked up on the PATH, and added as a command.
+func LookupCmd(name string) (*Cmd, bool) {
+	cmd, ok := Cmds[name]
+	if ok {
+		return cmd, true
+	}
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, false
+	}
+
+	path, err := exec.LookPath(PluginPrefix + name)
+	if err != nil {
+		return nil, false
+	}
+
+	pluginName, ok := pluginName(path)
+	if !ok || pluginName != name {
+		return nil, false
+	}
+
+	cmd = pluginCmd(name, path)
+	Cmds[name] = cmd
+	return cmd, true
+}
+
+// AddPlugins adds a command for each plugin on the PATH, commands that
+// already exist aren't replaced. It's only needed to list the commands,
+// LookupCmd finds a single plugin.
+func AddPlugins() {
+	for name, path := range FindPlugins(os.Getenv("PATH")) {
+		if _, ok := Cmds[name]; ok {
+			continue
+		}
+
+		Cmds[name] = pluginCmd(name, path)
+	}
+}
+
+// FindPlugins finds the plugin executables in the directories in path, by
+// name. If a plugin is in multiple directories the first one is used.
+func FindPlugins(path string) map[string]string {
+	plugins := make(map[string]string)
+
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			dir = "."
+		}
+
+		matches, err := filepath.Glob(filepath.Join(dir, PluginPrefix+"*"))
+		if err != nil {
+			continue
+		}
+
+		for _, match := range matches {
+			name, ok := pluginName(match)
+			if !ok {
+				continue
+			}
+
+			if _, ok := plugins[name]; !ok {
+				plugins[name] = match
+			}
+		}
+	}
+
+	return plugins
+}
+
+// pluginName gets the command name for a plugin path, and checks if it's
+// an executable.
+func pluginName(path string) (string, bool) {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return "", false
+	}
+	name := strings.TrimPrefix(filepath.Base(path), PluginPrefix)
+
+	// Windows uses extensions for executables, e.g. bowery-seed.exe.
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(name))
+		exts := strings.ToLower(os.Getenv("PATHEXT"))
+		if exts == "" {
+			exts = ".com;.exe;.bat;.cmd"
+		}
+
+		for _, e := range filepath.SplitList(exts) {
+			if ext != "" && ext == e {
+				return strings.TrimSuffix(name, filepath.Ext(name)), name != ext
+			}
+		}
+
+		return "", false
+	}
+
+	return name, name != "" && info.Mode()&0111 != 0
+}
+
+// pluginCmd creates a command that runs the plugin at path.
+func pluginCmd(name, path string) *Cmd {
+	cmd := &Cmd{
+		Run: func(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
+			return pluginRun(keen, rollbar, name, path, args...)
+		},
+		Usage: name + " [args]",
+		Short: "Plugin at " + path + ".",
+	}
+	cmd.Description = "Runs the plugin at " + path + " with the arguments. The plugin gets the\n" +
+		"app's root directory, state path and the api address in the BOWERY_APP_ROOT,\n" +
+		"BOWERY_STATE_PATH and BOWERY_API_ADDR environment variables."
+
+	return cmd
+}
+
+func pluginRun(keen *keen.Client, rollbar *rollbar.Client, name, path string, args ...string) int {
+	// Let the plugin handle interrupts.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	env, err := pluginEnv()
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+
+	plugin := exec.Command(path, args...)
+	plugin.Env = append(os.Environ(), env...)
+	plugin.Stdin = os.Stdin
+	plugin.Stdout = os.Stdout
+	plugin.Stderr = os.Stderr
+
+	keen.AddEvent("bowery plugin", map[string]string{"name": name})
+	log.Debug("Running plugin", path, args)
+
+	err = plugin.Run()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+				return status.ExitStatus()
+			}
+
+			return 1
+		}
+
+		rollbar.Report(errors.NewStackError(err))
+		return 1
+	}
+
+	return 0
+}
+
+// pluginEnv gets the environment variables given to plugins. The app
+// variables are empty if the directory isn't a connected app.
+func pluginEnv() ([]string, error) {
+	root := ""
+	statePath := ""
+
+	// GetState changes to the apps root, so change back after.
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, errors.NewStackError(err)
+	}
+	defer os.Chdir(wd)
+
+	state, err := db.GetState()
+	if err != nil && err != errors.ErrNotConnected {
+		return nil, err
+	}
+	if err == nil {
+		root, err = os.Getwd()
+		if err != nil {
+			return nil, errors.NewStackError(err)
+		}
+
+		statePath = filepath.Join(root, state.Path)
+	}
+
+	return []string{
+		"BOWERY_APP_ROOT=" + root,
+		"BOWERY_STATE_PATH=" + statePath,
+		"BOWERY_API_ADDR=" + api.BasePath,
+	}, nil
+}
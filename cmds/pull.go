@@ -22,9 +22,10 @@ import (
 
 func init() {
 	Cmds["pull"] = &Cmd{
-		Run:   pullRun,
-		Usage: "pull [id or name]",
-		Short: "Pull down an application and its code.",
+		Run:      pullRun,
+		Usage:    "pull [id or name]",
+		Short:    "Pull down an application and its code.",
+		Complete: completeApps,
 	}
 }
 
@@ -14,9 +14,10 @@ import (
 
 func init() {
 	Cmds["remove"] = &Cmd{
-		Run:   removeRun,
-		Usage: "remove <names>",
-		Short: "Remove services from your application.",
+		Run:      removeRun,
+		Usage:    "remove <names>",
+		Short:    "Remove services from your application.",
+		Complete: completeServices,
 	}
 }
 
@@ -2,25 +2,48 @@
 package cmds
 
 import (
+	"bytes"
 	"fmt"
 	"os"
-	"strings"
+	"time"
 
 	"github.com/Bowery/bowery/api"
 	"github.com/Bowery/bowery/db"
-	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/bowery/delancey"
+	"github.com/Bowery/bowery/hooks"
+	"github.com/Bowery/bowery/logs"
 	"github.com/Bowery/bowery/rollbar"
+	"github.com/Bowery/bowery/ssh"
 	"github.com/Bowery/gopackages/keen"
 	"github.com/Bowery/gopackages/log"
 	"github.com/Bowery/gopackages/schemas"
 )
 
 func init() {
-	Cmds["restart"] = &Cmd{
-		Run:   restartRun,
-		Usage: "restart <name>",
-		Short: "Restart a service.",
+	cmd := &Cmd{
+		Run:      restartRun,
+		Usage:    "restart <name>",
+		Short:    "Restart a service.",
+		Complete: completeServices,
 	}
+	cmd.Description = "Restarts a service in a new container. Restart waits for the service to\n" +
+		"be healthy, up to `bowery config timeout` seconds, to add your ssh key to it,\n" +
+		"then runs the service's restart hook and prints its output."
+
+	Cmds["restart"] = cmd
+}
+
+// recordPrinter prints the log records written to it.
+type recordPrinter struct{}
+
+func (recordPrinter) Write(b []byte) (int, error) {
+	for _, line := range bytes.Split(b, []byte("\n")) {
+		if len(line) > 0 {
+			printRecord(logs.ParseRecord(line), 0)
+		}
+	}
+
+	return len(b), nil
 }
 
 func restartRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
@@ -35,6 +58,7 @@ func restartRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int
 		rollbar.Report(err)
 		return 1
 	}
+	hooks.Timeout = configSeconds(dev, "hooktimeout", hooks.Timeout)
 
 	state, err := db.GetState()
 	if err != nil {
@@ -57,8 +81,7 @@ func restartRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int
 
 	// Handle no service found.
 	if service == nil {
-		log.Fprintln(os.Stderr, "red", errors.ErrInvalidService, args[0])
-		log.Println("yellow", "Valid services:", strings.Join(services, ", "))
+		printInvalidService(args[0], services)
 		return 1
 	}
 	log.Debug("Found service", service.Name)
@@ -69,10 +92,24 @@ func restartRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int
 		return 1
 	}
 
+	// The container was replaced, so trust its new host key.
+	err = ssh.RemoveHostKey(service.SSHAddr)
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+
 	if newService != nil {
 		state.App.Services[serviceIdx] = newService
 		service = newService
 
+		// The new address may have been pinned for an older container.
+		err = ssh.RemoveHostKey(service.SSHAddr)
+		if err != nil {
+			rollbar.Report(err)
+			return 1
+		}
+
 		err = state.Save()
 		if err != nil {
 			rollbar.Report(err)
@@ -80,6 +117,34 @@ func restartRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int
 		}
 	}
 
+	// The new container doesn't have the developers key, add it once it's
+	// ready so ssh works like after connect.
+	key, err := ssh.CreateKey()
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+
+	err = waitForServices([]*schemas.Service{service}, configSeconds(dev, "timeout", 2*time.Minute))
+	if err == nil {
+		err = delancey.AddKey(service.SatelliteAddr, ssh.AuthorizedKey(key))
+	}
+	if err != nil {
+		log.Debug("Adding ssh key to", service.Name, "failed:", err)
+		log.Println("yellow", "Unable to add your ssh key to", service.Name+".",
+			"Restart again if `bowery ssh` doesn't work.")
+	}
+
+	// Run the restart hook, it isn't required for the restart to succeed so
+	// failures are only warned about. Its output is printed since connect
+	// may be writing output.log.
+	config, err := db.GetServices()
+	if err != nil {
+		log.Println("yellow", err)
+	} else {
+		runHook(service.Name, config.Data[service.Name], hooks.Restart, recordPrinter{})
+	}
+
 	keen.AddEvent("bowery restart", map[string]string{
 		"name":  service.Name,
 		"appId": state.App.ID,
@@ -4,11 +4,9 @@ package cmds
 import (
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/Bowery/bowery/api"
 	"github.com/Bowery/bowery/db"
-	"github.com/Bowery/bowery/errors"
 	"github.com/Bowery/bowery/prompt"
 	"github.com/Bowery/bowery/rollbar"
 	"github.com/Bowery/gopackages/keen"
@@ -18,9 +16,10 @@ import (
 
 func init() {
 	Cmds["save"] = &Cmd{
-		Run:   saveRun,
-		Usage: "save <name>",
-		Short: "Save a service.",
+		Run:      saveRun,
+		Usage:    "save <name>",
+		Short:    "Save a service.",
+		Complete: completeServices,
 	}
 }
 
@@ -56,8 +55,7 @@ func saveRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
 
 	// Handle no service found.
 	if service == nil {
-		log.Fprintln(os.Stderr, "red", errors.ErrInvalidService, args[0])
-		log.Println("yellow", "Valid services:", strings.Join(services, ", "))
+		printInvalidService(args[0], services)
 		return 1
 	}
 	log.Debug("Found service", service.Name, "public addr:", service.PublicAddr)
@@ -2,6 +2,8 @@
 package cmds
 
 import (
+	"os"
+
 	"github.com/Bowery/bowery/api"
 	"github.com/Bowery/bowery/rollbar"
 	"github.com/Bowery/gopackages/keen"
@@ -16,7 +18,21 @@ func init() {
 	}
 }
 
+// searchResult is the images found for a query, printed by
+// `bowery --json search`.
+type searchResult struct {
+	Query  string       `json:"query"`
+	Images []*imageInfo `json:"images"`
+}
+
+type imageInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
 func searchRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
+	jsonOutput := Cmds["search"].JSON
+	results := make([]*searchResult, 0, len(args))
 	if len(args) <= 0 {
 		args = append(args, "")
 	}
@@ -33,6 +49,16 @@ func searchRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
 			return 1
 		}
 
+		if jsonOutput {
+			result := &searchResult{Query: name, Images: make([]*imageInfo, len(images))}
+			for i, image := range images {
+				result.Images[i] = &imageInfo{Name: image.Name, Description: image.Description}
+			}
+
+			results = append(results, result)
+			continue
+		}
+
 		if len(images) <= 0 {
 			log.Println("", "No Result for '"+name+"' were found.")
 		} else {
@@ -51,5 +77,13 @@ func searchRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
 			}
 		}
 	}
+
+	if jsonOutput {
+		err := writeJSON(os.Stdout, results)
+		if err != nil {
+			rollbar.Report(err)
+			return 1
+		}
+	}
 	return 0
 }
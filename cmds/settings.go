@@ -2,9 +2,6 @@
 package cmds
 
 import (
-	"fmt"
-	"net/mail"
-	"os"
 	"strings"
 
 	"github.com/Bowery/bowery/broome"
@@ -16,42 +13,23 @@ import (
 	"github.com/Bowery/gopackages/log"
 )
 
-// handlers only take keen to log events. They return errors, so the runner reports to rollbar
-type settingHandler func(keen *keen.Client, args ...string) error
-
-var settingHandlers = map[string]settingHandler{
-	"password": password,
-}
-
 func init() {
 	Cmds["settings"] = &Cmd{
-		Run:   settingsRun,
 		Usage: "settings <setting>",
 		Short: "Edit your Bowery account settings.",
+		Subcmds: map[string]*Cmd{
+			"password": &Cmd{
+				Run:   passwordRun,
+				Usage: "settings password",
+				Short: "Request a password reset.",
+			},
+		},
 	}
 }
 
-func settingsRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
-	var handler settingHandler
-	if len(args) > 0 {
-		var ok bool
-		handler, ok = settingHandlers[args[0]]
-		if !ok {
-			log.Fprintln(os.Stderr, "red", "Invalid choice.")
-			return 1
-		}
-	} else {
-		fmt.Fprintln(os.Stderr,
-			"Usage: bowery", Cmds["settings"].Usage, "\n\n"+Cmds["settings"].Short+"\n")
-		fmt.Fprintln(os.Stderr, "Settings:")
-		for name, _ := range settingHandlers {
-			fmt.Fprintln(os.Stderr, " ", name)
-		}
-		return 2
-	}
-
-	// if handler returns error, report it
-	if err := handler(keen); err != nil {
+func passwordRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
+	err := password(keen)
+	if err != nil {
 		rollbar.Report(err)
 		return 1
 	}
@@ -79,19 +57,9 @@ func password(keen *keen.Client, args ...string) error {
 		return nil
 	}
 
-	valid := false
-	email := ""
-	for !valid {
-		email, err = prompt.Basic("Email", true)
-		if err != nil {
-			return err
-		}
-		_, err = mail.ParseAddress(email)
-		if err != nil {
-			log.Println("yellow", "Try again! Valid email address required.")
-		} else {
-			valid = true
-		}
+	email, err := promptEmail()
+	if err != nil {
+		return err
 	}
 
 	if err = broome.ResetPassword(email); err != nil {
@@ -2,7 +2,6 @@
 package cmds
 
 import (
-	"net/mail"
 	"os"
 	"strings"
 
@@ -39,20 +38,10 @@ func signupRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
 	}
 	log.Debug("Collected name", name)
 
-	validEmail := false
-	email := ""
-	for !validEmail {
-		email, err = prompt.Basic("Email", true)
-		if err != nil {
-			rollbar.Report(err)
-			return 1
-		}
-		_, err = mail.ParseAddress(email)
-		if err == nil {
-			validEmail = true
-		} else {
-			log.Println("yellow", "Try again! Valid email address required.")
-		}
+	email, err := promptEmail()
+	if err != nil {
+		rollbar.Report(err)
+		return 1
 	}
 	log.Debug("Collected email", email)
 
@@ -63,12 +52,16 @@ func signupRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
 	}
 	log.Debug("Collected password", pass)
 
-	conf, err := prompt.Password("Confirm Password")
-	if err != nil {
-		rollbar.Report(err)
-		return 1
+	// There's nothing to confirm without a terminal.
+	conf := pass
+	if !prompt.NonInteractive {
+		conf, err = prompt.Password("Confirm Password")
+		if err != nil {
+			rollbar.Report(err)
+			return 1
+		}
+		log.Debug("Collected confirmation password", conf)
 	}
-	log.Debug("Collected confirmation password", conf)
 
 	if pass != conf {
 		log.Fprintln(os.Stderr, "red", errors.ErrMismatchPass, "Try again.")
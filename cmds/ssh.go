@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	"github.com/Bowery/bowery/db"
-	"github.com/Bowery/bowery/errors"
 	"github.com/Bowery/bowery/rollbar"
 	"github.com/Bowery/bowery/ssh"
 	"github.com/Bowery/gopackages/keen"
@@ -16,11 +15,24 @@ import (
 )
 
 func init() {
-	Cmds["ssh"] = &Cmd{
+	cmd := &Cmd{
 		Run:   sshRun,
-		Usage: "ssh <name>",
+		Usage: "ssh <name> [command]",
 		Short: "Connect to a service via ssh.",
+		Complete: func(args ...string) []string {
+			// Only the service is completed, not the command.
+			if len(args) > 0 {
+				return nil
+			}
+
+			return completeServices()
+		},
 	}
+	cmd.Description = "Opens a shell on a service via ssh, or runs the command if one is given.\n" +
+		"Stdin may be piped, e.g. `bowery ssh db psql < dump.sql`. The exit status\n" +
+		"is the exit status of the shell or command."
+
+	Cmds["ssh"] = cmd
 }
 
 func sshRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
@@ -48,17 +60,22 @@ func sshRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
 
 	// Handle no service found.
 	if service == nil {
-		log.Fprintln(os.Stderr, "red", errors.ErrInvalidService, args[0])
-		log.Println("yellow", "Valid services:", strings.Join(services, ", "))
+		printInvalidService(args[0], services)
 		return 1
 	}
 	log.Debug("Found service", service.Name, "ssh addr:", service.SSHAddr)
 
-	err = ssh.Shell(state.App, service)
+	// Run the command if given, with a tty if we're in a terminal.
+	var status int
+	if len(args) > 1 {
+		status, err = ssh.Exec(service, strings.Join(args[1:], " "), ssh.IsTerminal())
+	} else {
+		status, err = ssh.Shell(state.App, service)
+	}
 	if err != nil {
 		rollbar.Report(err)
 		return 1
 	}
 
-	return 0
+	return status
 }
@@ -0,0 +1,69 @@
+// Copyright 2013-2014 Bowery, Inc.
+package cmds
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/Bowery/bowery/db"
+	"github.com/Bowery/bowery/rollbar"
+	"github.com/Bowery/bowery/ssh"
+	"github.com/Bowery/gopackages/keen"
+	"github.com/Bowery/gopackages/log"
+)
+
+func init() {
+	cmd := &Cmd{
+		Run:   sshConfigRun,
+		Usage: "ssh-config [-file path]",
+		Short: "Export an OpenSSH config for your services.",
+		Flags: flag.NewFlagSet("ssh-config", flag.ContinueOnError),
+	}
+	cmd.Description = "Exports OpenSSH host entries for the services in the current app, aliased\n" +
+		"as <app>-<service>. Tools like scp, rsync and editors with remote ssh\n" +
+		"support can then connect with `ssh <app>-<service>`, trusting the host keys\n" +
+		"pinned in .bowery/known_hosts.\n\n" +
+		"With -file the app's entries in the file are replaced, so it can be ran again\n" +
+		"after reconnecting. Include it from ~/.ssh/config, e.g.\n" +
+		"`Include ~/.ssh/bowery_config`."
+	cmd.Flags.String("file", "", "Write the entries to a file instead of printing them.")
+
+	Cmds["ssh-config"] = cmd
+}
+
+func sshConfigRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
+	path := Cmds["ssh-config"].flagString("file")
+	state, err := db.GetState()
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+
+	// Make sure the key exists, otherwise the entries can't connect.
+	_, err = ssh.GetKey()
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+
+	if path == "" {
+		config, err := ssh.Config(state.App)
+		if err != nil {
+			rollbar.Report(err)
+			return 1
+		}
+
+		fmt.Print(config)
+		return 0
+	}
+
+	err = ssh.UpdateConfig(path, state.App)
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+	log.Println("magenta", "Updated", path, "with", len(state.App.Services), "service(s).")
+
+	keen.AddEvent("bowery ssh-config", map[string]string{"appId": state.App.ID})
+	return 0
+}
@@ -0,0 +1,198 @@
+// Copyright 2013-2014 Bowery, Inc.
+package cmds
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Bowery/bowery/api"
+	"github.com/Bowery/bowery/db"
+	"github.com/Bowery/bowery/delancey"
+	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/bowery/prompt"
+	"github.com/Bowery/bowery/rollbar"
+	"github.com/Bowery/gopackages/keen"
+	"github.com/Bowery/gopackages/log"
+	"github.com/Bowery/gopackages/schemas"
+)
+
+// statusInterval is how often the status is updated with -watch.
+var statusInterval = 2 * time.Second
+
+// serviceStatus is the status of a single service.
+type serviceStatus struct {
+	Name        string            `json:"name"`
+	Image       string            `json:"image"`
+	Healthy     bool              `json:"healthy"`
+	State       string            `json:"state"`
+	Address     string            `json:"address"`
+	Ports       map[string]string `json:"ports"`
+	RestartedAt time.Time         `json:"restartedAt"`
+}
+
+func init() {
+	cmd := &Cmd{
+		Run:   statusRun,
+		Usage: "status [-watch] [-json]",
+		Short: "Show the health of your application's services.",
+		Flags: flag.NewFlagSet("status", flag.ContinueOnError),
+	}
+	cmd.Description = "Shows the image, health, address, custom ports and last restart of the\n" +
+		"services in the current app. It doesn't require `bowery connect` to be running."
+	cmd.Flags.Bool("watch", false, "Keep updating the status every few seconds.")
+	cmd.Flags.Bool("json", false, "Print the status as JSON.")
+
+	Cmds["status"] = cmd
+}
+
+func statusRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, os.Kill)
+	defer signal.Stop(signals)
+	cmd := Cmds["status"]
+	watch := cmd.flagBool("watch")
+
+	dev, err := getDeveloper()
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+
+	state, err := db.GetState()
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+
+	keen.AddEvent("bowery status", map[string]interface{}{
+		"appId": state.App.ID,
+		"watch": watch,
+	})
+
+	out := prompt.NewAnsiWriter(os.Stdout)
+	for {
+		statuses := getStatuses(state, dev.Token)
+
+		if cmd.flagBool("json") || cmd.JSON {
+			var data []byte
+			if watch {
+				data, err = json.Marshal(statuses)
+			} else {
+				data, err = json.MarshalIndent(statuses, "", "  ")
+			}
+			if err != nil {
+				rollbar.Report(errors.NewStackError(err))
+				return 1
+			}
+
+			fmt.Println(string(data))
+		} else {
+			if watch {
+				fmt.Fprint(out, "\x1b[H\x1b[2J") // Clear the screen.
+				log.Println("magenta", "Every", statusInterval.String()+":", "bowery status")
+			}
+
+			printStatuses(statuses)
+		}
+
+		if !watch {
+			return 0
+		}
+
+		select {
+		case <-time.After(statusInterval):
+		case <-signals:
+			return 0
+		}
+	}
+}
+
+// getStatuses gets the status of each service concurrently.
+func getStatuses(state *db.State, token string) []*serviceStatus {
+	var wg sync.WaitGroup
+	statuses := make([]*serviceStatus, len(state.App.Services))
+
+	for i, service := range state.App.Services {
+		image := service.Image
+		if config, ok := state.Config[service.Name]; ok && config.Image != "" {
+			image = config.Image
+		}
+
+		statuses[i] = &serviceStatus{
+			Name:    service.Name,
+			Image:   image,
+			State:   "unknown",
+			Address: serviceAddr(state, service, ""),
+			Ports:   service.CustomPorts,
+		}
+
+		wg.Add(1)
+		go func(status *serviceStatus, service *schemas.Service) {
+			defer wg.Done()
+			status.Healthy = delancey.CheckHealth(service.SatelliteAddr) == nil
+
+			res, err := api.GetServiceStatus(service.DockerID, token)
+			if err != nil {
+				log.Debug("Getting status for", service.Name, "failed:", err)
+				return
+			}
+
+			status.State = res.State
+			status.RestartedAt = res.RestartedAt
+		}(statuses[i], service)
+	}
+
+	wg.Wait()
+	return statuses
+}
+
+// printStatuses prints a table of statuses, unhealthy services are red.
+func printStatuses(statuses []*serviceStatus) {
+	var buf bytes.Buffer
+	table := tabwriter.NewWriter(&buf, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(table, "NAME\tIMAGE\tHEALTH\tADDRESS\tPORTS\tRESTARTED")
+
+	for _, status := range statuses {
+		health := "healthy"
+		if !status.Healthy {
+			health = "unreachable"
+		}
+		health += " (" + status.State + ")"
+
+		ports := make([]string, 0, len(status.Ports))
+		for port := range status.Ports {
+			ports = append(ports, port)
+		}
+		sort.Strings(ports)
+
+		restarted := "-"
+		if !status.RestartedAt.IsZero() {
+			restarted = status.RestartedAt.Local().Format("Jan 2 15:04:05")
+		}
+
+		fmt.Fprintln(table, strings.Join([]string{status.Name, status.Image, health,
+			status.Address, strings.Join(ports, ","), restarted}, "\t"))
+	}
+	table.Flush()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for i, line := range lines {
+		color := ""
+		if i == 0 {
+			color = "cyan"
+		} else if !statuses[i-1].Healthy {
+			color = "red"
+		}
+
+		log.Println(color, line)
+	}
+}
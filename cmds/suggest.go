@@ -0,0 +1,138 @@
+// Copyright 2013-2014 Bowery, Inc.
+package cmds
+
+import (
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/gopackages/log"
+)
+
+// Names gets the sorted names of the commands that aren't hidden.
+func Names(cmds map[string]*Cmd) []string {
+	names := make([]string, 0, len(cmds))
+	for name, cmd := range cmds {
+		if !cmd.Hidden {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Suggest gets the candidates similar to name, closest first. Candidates
+// starting with name, or a couple of typos away from it are similar.
+func Suggest(name string, candidates []string) []string {
+	maxDist := len(name) / 2
+	if maxDist > 2 {
+		maxDist = 2
+	}
+	if maxDist < 1 {
+		maxDist = 1
+	}
+
+	dists := make(map[string]int)
+	suggestions := make([]string, 0)
+	for _, candidate := range candidates {
+		dist := editDistance(name, candidate)
+		if name != "" && strings.HasPrefix(candidate, name) {
+			dist = 0
+		}
+
+		if dist <= maxDist {
+			dists[candidate] = dist
+			suggestions = append(suggestions, candidate)
+		}
+	}
+
+	sort.Sort(&byDistance{suggestions, dists})
+	return suggestions
+}
+
+// PrintSuggestions writes the candidates similar to name to out, returning
+// false if there are none.
+func PrintSuggestions(out io.Writer, name string, candidates []string) bool {
+	suggestions := Suggest(name, candidates)
+	if len(suggestions) <= 0 {
+		return false
+	}
+
+	if len(suggestions) == 1 {
+		log.Fprintln(out, "yellow", "Did you mean this?")
+	} else {
+		log.Fprintln(out, "yellow", "Did you mean one of these?")
+	}
+	for _, suggestion := range suggestions {
+		log.Fprintln(out, "", "  "+suggestion)
+	}
+
+	return true
+}
+
+// printInvalidService prints the error for a service that doesn't exist,
+// with the services similar to it or all of them.
+func printInvalidService(name string, services []string) {
+	log.Fprintln(os.Stderr, "red", errors.ErrInvalidService, name)
+
+	if !PrintSuggestions(os.Stderr, name, services) {
+		log.Println("yellow", "Valid services:", strings.Join(services, ", "))
+	}
+}
+
+// editDistance gets the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	prev := make([]int, len(t)+1)
+	cur := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		cur[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+
+			cur[j] = prev[j-1] + cost
+			if prev[j]+1 < cur[j] {
+				cur[j] = prev[j] + 1
+			}
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(t)]
+}
+
+// byDistance sorts names by their distance, then alphabetically.
+type byDistance struct {
+	names []string
+	dists map[string]int
+}
+
+func (b *byDistance) Len() int {
+	return len(b.names)
+}
+
+func (b *byDistance) Swap(i, j int) {
+	b.names[i], b.names[j] = b.names[j], b.names[i]
+}
+
+func (b *byDistance) Less(i, j int) bool {
+	di, dj := b.dists[b.names[i]], b.dists[b.names[j]]
+	if di != dj {
+		return di < dj
+	}
+
+	return b.names[i] < b.names[j]
+}
@@ -0,0 +1,107 @@
+// Copyright 2013-2014 Bowery, Inc.
+package cmds
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/Bowery/bowery/db"
+	"github.com/Bowery/bowery/delancey"
+	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/bowery/rollbar"
+	"github.com/Bowery/gopackages/keen"
+	"github.com/Bowery/gopackages/log"
+	"github.com/Bowery/gopackages/schemas"
+)
+
+func init() {
+	cmd := &Cmd{
+		Run:   testRun,
+		Usage: "test [names]",
+		Short: "Run the test command for services.",
+	}
+	cmd.Description = "Runs the test command for the given services, or all services with a\n" +
+		"test command if none are given. The output is streamed as the tests run,\n" +
+		"and the exit status is the exit status of the first failing test command."
+
+	Cmds["test"] = cmd
+}
+
+func testRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
+	state, err := db.GetState()
+	if err != nil {
+		rollbar.Report(err)
+		return 1
+	}
+
+	// Create slices of service names, and find the requested services.
+	services := make([]*schemas.Service, 0)
+	names := make([]string, len(state.App.Services))
+	for i, v := range state.App.Services {
+		names[i] = v.Name
+		config := state.Config[v.Name]
+
+		if len(args) <= 0 && config != nil && config.Test != "" {
+			services = append(services, v)
+		}
+	}
+
+	for _, name := range args {
+		var service *schemas.Service
+		for _, v := range state.App.Services {
+			if name == v.Name {
+				service = v
+				break
+			}
+		}
+
+		// Handle no service found.
+		if service == nil {
+			printInvalidService(name, names)
+			return 1
+		}
+
+		config := state.Config[service.Name]
+		if config == nil || config.Test == "" {
+			log.Println("yellow", "Service", service.Name, "has no test command, skipping.")
+			continue
+		}
+
+		services = append(services, service)
+	}
+
+	if len(services) <= 0 {
+		rollbar.Report(errors.ErrNoTests)
+		return 1
+	}
+
+	status := 0
+	for _, service := range services {
+		log.Println("cyan", "Running tests for", service.Name+".")
+		log.Debug("Running", state.Config[service.Name].Test, "on", service.SatelliteAddr)
+
+		code, err := delancey.Test(service.SatelliteAddr, service.Name, os.Stdout)
+		if err != nil {
+			rollbar.Report(err)
+			return 1
+		}
+
+		if code != 0 {
+			log.Fprintln(os.Stderr, "red", "Tests failed for", service.Name,
+				"(exit status "+strconv.Itoa(code)+").")
+			if status == 0 {
+				status = code
+			}
+			continue
+		}
+
+		log.Println("magenta", "Tests passed for", service.Name+".")
+	}
+
+	keen.AddEvent("bowery test", map[string]interface{}{
+		"services": len(services),
+		"appId":    state.App.ID,
+		"status":   status,
+	})
+	return status
+}
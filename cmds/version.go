@@ -3,6 +3,7 @@ package cmds
 
 import (
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 
@@ -23,6 +24,16 @@ func init() {
 func versionRun(keen *keen.Client, rollbar *rollbar.Client, args ...string) int {
 	keen.AddEvent("cli get version", map[string]string{"installed": version.Version})
 
+	if Cmds["version"].JSON {
+		err := writeJSON(os.Stdout, map[string]string{"version": version.Version})
+		if err != nil {
+			rollbar.Report(err)
+			return 1
+		}
+
+		return 0
+	}
+
 	log.Println("", version.Version)
 	return 0
 }
@@ -19,6 +19,7 @@ type Developer struct {
 	Token     string             `json:"token"`
 	Developer *schemas.Developer `json:"developer"`
 	Config    map[string]string  `json:"config"`
+	Aliases   map[string]string  `json:"aliases,omitempty"`
 	path      string
 }
 
@@ -19,6 +19,7 @@ type Service struct {
 	Test  string            `json:"test,omitempty"`
 	Init  string            `json:"init,omitempty"`
 	Env   map[string]string `json:"env,omitempty"`
+	Hooks map[string]string `json:"hooks,omitempty"`
 }
 
 // Services contains a map of services by name.
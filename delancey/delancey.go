@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"io"
+	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Bowery/bowery/db"
 	"github.com/Bowery/bowery/errors"
@@ -227,8 +229,160 @@ func Update(url, serviceName, fullPath, name, status string) error {
 	return errors.NewStackError(uploadRes)
 }
 
-// CheckHealth checks to see if the container is up
+// AddKey adds a public key to the authorized keys on the satellite.
+func AddKey(url string, key []byte) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	err := writer.WriteField("key", string(key))
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+
+	res, err := http.Post("http://"+url+"/keys", writer.FormDataContentType(), &body)
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+	defer res.Body.Close()
+
+	// Decode json response.
+	keyRes := new(responses.Res)
+	decoder := json.NewDecoder(res.Body)
+	err = decoder.Decode(keyRes)
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+
+	// Created, so no error.
+	if keyRes.Status == "created" {
+		return nil
+	}
+
+	return errors.NewStackError(keyRes)
+}
+
+// Backoff settings for WaitHealthy.
+var (
+	healthClient   = &http.Client{Timeout: 5 * time.Second}
+	minHealthDelay = 100 * time.Millisecond
+	maxHealthDelay = 5 * time.Second
+)
+
+// CheckHealth checks to see if the container is up and ready, the satellite
+// must respond with a 200 and "ok".
 func CheckHealth(url string) error {
-	_, err := http.Get("http://" + url + "/healthz")
-	return err
+	res, err := healthClient.Get("http://" + url + "/healthz")
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(res.Body, 1024))
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+	status := strings.TrimSpace(string(body))
+
+	if res.StatusCode != http.StatusOK || !strings.EqualFold(status, "ok") {
+		return errors.Newf(errors.ErrUnhealthyTmpl, res.Status, status)
+	}
+
+	return nil
+}
+
+// WaitHealthy checks the containers health until it's ready or the timeout
+// has passed, backing off exponentially between checks. Progress is called
+// with the time waited so far and the error after each failed check.
+func WaitHealthy(url string, timeout time.Duration, progress func(time.Duration, error)) error {
+	start := time.Now()
+	delay := minHealthDelay
+
+	for {
+		err := CheckHealth(url)
+		if err == nil {
+			return nil
+		}
+
+		waited := time.Since(start)
+		if progress != nil {
+			progress(waited, err)
+		}
+		if waited+delay > timeout {
+			return err
+		}
+
+		<-time.After(delay)
+		delay *= 2
+		if delay > maxHealthDelay {
+			delay = maxHealthDelay
+		}
+	}
+}
+
+// Test runs the services test command on the satellite, streaming the output
+// to out. The exit status of the remote command is returned.
+func Test(url, serviceName string, out io.Writer) (int, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	// Get current app and add fields for the test command and env.
+	state, err := db.GetState()
+	if err != nil {
+		return 1, err
+	}
+
+	if service := state.Config[serviceName]; service != nil {
+		err = writer.WriteField("test", service.Test)
+		if err == nil && service.Env != nil {
+			var envData []byte
+			envData, err = json.Marshal(service.Env)
+			if err == nil {
+				err = writer.WriteField("env", string(envData))
+			}
+		}
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		return 1, err
+	}
+
+	res, err := http.Post("http://"+url+"/test", writer.FormDataContentType(), &body)
+	if err != nil {
+		if responses.IsRefusedConn(err) {
+			err = errors.ErrContainerConnect
+		}
+
+		return 1, errors.NewStackError(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		testRes := new(responses.Res)
+		decoder := json.NewDecoder(res.Body)
+		err = decoder.Decode(testRes)
+		if err != nil {
+			return 1, errors.NewStackError(err)
+		}
+
+		return 1, errors.NewStackError(testRes)
+	}
+
+	// Stream the output, the exit status is sent as a trailer once the
+	// command completes.
+	_, err = io.Copy(out, res.Body)
+	if err != nil {
+		return 1, errors.NewStackError(err)
+	}
+
+	code, err := strconv.Atoi(res.Trailer.Get("Exit-Code"))
+	if err != nil {
+		return 1, errors.NewStackError(errors.ErrTestStatus)
+	}
+
+	return code, nil
 }
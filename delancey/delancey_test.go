@@ -3,6 +3,7 @@ package delancey
 // TODO (thebyrd) Delancey Methods rely on the .bowery/state file to be created. Not sure how to mock this out for the tests.
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -137,3 +138,59 @@ func updateHandler(rw http.ResponseWriter, req *http.Request) {
 	body, _ := json.Marshal(res)
 	rw.Write(body)
 }
+
+func TestTestSuccessful(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(testHandler))
+	defer server.Close()
+
+	addr, _ := url.Parse(server.URL)
+
+	var out bytes.Buffer
+	code, err := Test(addr.Host, TestService.Name, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if code != 2 {
+		t.Error("Test returned exit status", code, "expected 2")
+	}
+
+	if out.String() != "FAIL\n" {
+		t.Error("Test output was", out.String())
+	}
+}
+
+func testHandler(rw http.ResponseWriter, req *http.Request) {
+	rw.Header().Set("Trailer", "Exit-Code")
+	rw.Write([]byte("FAIL\n"))
+	rw.Header().Set("Exit-Code", "2")
+}
+
+func TestCheckHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(healthHandler))
+	defer server.Close()
+
+	addr, _ := url.Parse(server.URL)
+	err := CheckHealth(addr.Host)
+	if err == nil {
+		t.Error("CheckHealth should fail for a 500 response.")
+	}
+
+	err = WaitHealthy(addr.Host, 5*time.Second, nil)
+	if err != nil {
+		t.Error("WaitHealthy failed", err)
+	}
+}
+
+var healthChecks = 0
+
+func healthHandler(rw http.ResponseWriter, req *http.Request) {
+	healthChecks++
+	if healthChecks < 3 {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("starting"))
+		return
+	}
+
+	rw.Write([]byte("ok\n"))
+}
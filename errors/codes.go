@@ -119,8 +119,9 @@ var errorCodes = []Error{
 	Error{
 		Code:  "17",
 		Title: ErrIORedirection.Error(),
-		Description: "IO redirection is not possible with Bowery ssh, this is because\n" +
-			"it needs to be connected to a terminal to get the window size.",
+		Description: "IO redirection is not possible when a tty is requested, e.g. `bowery exec -t`,\n" +
+			"this is because it needs to be connected to a terminal to get the window size.\n" +
+			"Prompts also require a terminal to read input from.",
 	},
 	Error{
 		Code:  "18",
@@ -181,6 +182,109 @@ var errorCodes = []Error{
 		Title:       ErrPathNotDirTmpl,
 		Description: "The path for a service must be a directory to sync changes.",
 	},
+	Error{
+		Code:  "28",
+		Title: ErrTestStatus.Error(),
+		Description: "The test command ran, but the service didn't report how it exited. This can\n" +
+			"happen if the connection drops while the tests are running. Run `bowery restart`\n" +
+			"if this problem persists.",
+	},
+	Error{
+		Code:  "29",
+		Title: ErrNoTests.Error(),
+		Description: "Tests are ran with the test command of a service. To add one specify a test\n" +
+			"command for the service in the bowery.json file, e.g. \"test\": \"make test\".",
+	},
+	Error{
+		Code:  "30",
+		Title: ErrNoKey.Error(),
+		Description: "Bowery ssh connects to services with a key unique to you. It's created and added\n" +
+			"to your services the next time you run `bowery connect`.",
+	},
+	Error{
+		Code:  "31",
+		Title: ErrHostKeyChangedTmpl,
+		Description: "The first time you connect to a service its host key is saved in .bowery/known_hosts,\n" +
+			"and later connections must present the same key. Restarting a service with\n" +
+			"`bowery restart` trusts its new key. If the service wasn't replaced, contact support.\n" +
+			"Otherwise remove the line for the service from .bowery/known_hosts.",
+	},
+	Error{
+		Code:  "32",
+		Title: ErrCopyTmpl,
+		Description: "Copying files to or from a service failed on the service, the reason is included\n" +
+			"in the message. Make sure the path exists on the service, e.g. with\n\n" +
+			"$ bowery exec <name> ls <path>\n",
+	},
+	Error{
+		Code:  "33",
+		Title: ErrInvalidSince.Error(),
+		Description: "The time given to `bowery logs -since` or `-until` must either be a duration\n" +
+			"from now, e.g. 30s, 10m or 2h, or an RFC 3339 time, e.g. 2014-06-01T15:04:05Z.",
+	},
+	Error{
+		Code:  "34",
+		Title: ErrInvalidConfigValueTmpl,
+		Description: "The value given to `bowery config` isn't valid for the key. The logsize and\n" +
+			"logfiles keys must be whole numbers, and logsize must be at least 1. The\n" +
+			"logsource key must be redis, http or auto. The timeout and hooktimeout keys\n" +
+			"must be a whole number of seconds, at least 1.",
+	},
+	Error{
+		Code:  "35",
+		Title: ErrNotReadyTmpl,
+		Description: "The service didn't pass its health check before the timeout, which defaults\n" +
+			"to 2 minutes. Images that take a long time to boot may need a longer timeout,\n" +
+			"e.g. `bowery config timeout 300` for 5 minutes.",
+	},
+	Error{
+		Code:  "36",
+		Title: ErrHookTmpl,
+		Description: "A hook set in the hooks section of a service in bowery.json exited with an\n" +
+			"error or ran for longer than a minute, set with `bowery config hooktimeout`.\n" +
+			"Its output is in `bowery logs`. Hooks run in the app's directory with the\n" +
+			"service name and event in the BOWERY_SERVICE and BOWERY_EVENT environment\n" +
+			"variables.",
+	},
+	Error{
+		Code:  "37",
+		Title: ErrNoAnswerTmpl,
+		Description: "With --yes or --non-interactive questions are answered yes and other input is\n" +
+			"read from environment variables named after the prompt, e.g. BOWERY_EMAIL and\n" +
+			"BOWERY_PASSWORD for `bowery login`. The variable was missing or invalid.",
+	},
+	Error{
+		Code:  "38",
+		Title: ErrServiceExistsTmpl,
+		Description: "`bowery add` with options doesn't ask before replacing a service in\n" +
+			"bowery.json. Run it with --force to replace it, or pick another name.",
+	},
+	Error{
+		Code:  "39",
+		Title: ErrAliasCommandTmpl,
+		Description: "Aliases are only used for names that aren't commands, so an alias with the\n" +
+			"name of a command or plugin would never run. Pick another name.",
+	},
+	Error{
+		Code:  "40",
+		Title: ErrRecursiveAliasTmpl,
+		Description: "An alias expands to itself, directly or through other aliases, e.g.\n" +
+			"`a = b` and `b = a`. Change one of them with `bowery config alias <name>`.",
+	},
+	Error{
+		Code:  "41",
+		Title: ErrUnsafeLinkTmpl,
+		Description: "`bowery cp` doesn't create symlinks that point outside of the copied path, or\n" +
+			"write files through symlinks, so a service can't overwrite files elsewhere\n" +
+			"on your computer. Copy the link's target instead.",
+	},
+	Error{
+		Code:  "42",
+		Title: ErrCopyServices.Error(),
+		Description: "`bowery cp` copies between a service and your machine, so only one of the\n" +
+			"paths can be prefixed with a service name. Copy the files to your machine\n" +
+			"first, then to the other service.",
+	},
 }
 
 func GetAll() []Error {
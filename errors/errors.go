@@ -37,17 +37,33 @@ var (
 	ErrContainerConnect = errors.New("Unable to connect to container. Run `bowery restart` if this problem persists. Error Code: 25")
 	ErrResetRequest     = errors.New("Unable to reset your password. Please Try again. Error Code: 26")
 	ErrInvalidEmail     = errors.New("Email does not match an existing user.")
+	ErrTestStatus       = errors.New("Unable to get the exit status of the tests. Error Code: 28")
+	ErrNoTests          = errors.New("No services have a test command. Add one to your bowery.json file. Error Code: 29")
+	ErrNoKey            = errors.New("No ssh key found. Run `bowery connect` to create one. Error Code: 30")
+	ErrInvalidSince     = errors.New("Invalid since time, use a duration like 10m or a time like 2014-06-01T15:04:05Z. Error Code: 33")
+	ErrCopyServices     = errors.New("Only one path can be on a service, copy through your machine instead. Error Code: 42")
 )
 
 // Error templates to be used with Newf.
 const (
-	ErrPathNotFoundTmpl = "The path for %s(%s) does not exist. Create it to continue. Error Code: 18"
-	ErrPathNotDirTmpl   = "The path for %s(%s) is not a directory. Error Code: 27"
-	ErrSyncTmpl         = "(%s): %s"
-	ErrLoginRetryTmpl   = "%s Try again."
-	ErrInvalidJSONTmpl  = "Invalid JSON in file %s. Error Code: 19"
-	ErrInvalidPortTmpl  = "%s is an invalid port. Try again. Error Code: 20"
-	ErrErrorsRange      = "Valid range: 0 - %d"
+	ErrPathNotFoundTmpl       = "The path for %s(%s) does not exist. Create it to continue. Error Code: 18"
+	ErrPathNotDirTmpl         = "The path for %s(%s) is not a directory. Error Code: 27"
+	ErrSyncTmpl               = "(%s): %s"
+	ErrLoginRetryTmpl         = "%s Try again."
+	ErrInvalidJSONTmpl        = "Invalid JSON in file %s. Error Code: 19"
+	ErrInvalidPortTmpl        = "%s is an invalid port. Try again. Error Code: 20"
+	ErrErrorsRange            = "Valid range: 0 - %d"
+	ErrHostKeyChangedTmpl     = "WARNING: The host key for %s(%s) has changed. Someone may be intercepting the connection. Error Code: 31"
+	ErrCopyTmpl               = "Unable to copy %s: %s Error Code: 32"
+	ErrInvalidConfigValueTmpl = "%s is an invalid value for %s. Try again. Error Code: 34"
+	ErrNotReadyTmpl           = "%s wasn't ready after %s. Run `bowery restart` if this problem persists. Error Code: 35"
+	ErrUnhealthyTmpl          = "Health check responded with %s: %s"
+	ErrHookTmpl               = "The %s hook for %s failed: %s Error Code: 36"
+	ErrNoAnswerTmpl           = "No valid answer for %s in non-interactive mode, set %s. Error Code: 37"
+	ErrServiceExistsTmpl      = "The service %s already exists. Use --force to replace it. Error Code: 38"
+	ErrAliasCommandTmpl       = "%s is already a command, aliases can't replace commands. Error Code: 39"
+	ErrRecursiveAliasTmpl     = "The alias %s refers to itself. Error Code: 40"
+	ErrUnsafeLinkTmpl         = "Unable to copy %s, it's a link outside of the copied path. Error Code: 41"
 )
 
 // Error function wrappers.
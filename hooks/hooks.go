@@ -0,0 +1,98 @@
+// Copyright 2013-2014 Bowery, Inc.
+// Package hooks runs the local commands services set in bowery.json to run
+// at points in the apps lifecycle.
+package hooks
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Bowery/bowery/db"
+	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/bowery/logs"
+)
+
+// Events hooks can be set for.
+const (
+	// Before the app connects.
+	PreConnect = "preconnect"
+
+	// After the initial upload to the service.
+	Upload = "upload"
+
+	// After a batch of file changes are synced to the service.
+	Sync = "sync"
+
+	// After the service restarts.
+	Restart = "restart"
+)
+
+// Timeout is how long a hook can run before it's killed.
+var Timeout = time.Minute
+
+// Run runs the services hook for the event, if it has one. The hooks output
+// is written to out as log records for the service. The service name and event
+// are in the BOWERY_SERVICE and BOWERY_EVENT environment variables.
+func Run(name string, service *db.Service, event string, out io.Writer) error {
+	if service == nil || service.Hooks[event] == "" {
+		return nil
+	}
+
+	cmd := shell(service.Hooks[event])
+	cmd.Env = append(os.Environ(), "BOWERY_SERVICE="+name, "BOWERY_EVENT="+event)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Start()
+	if err != nil {
+		return errors.Newf(errors.ErrHookTmpl, event, name, err)
+	}
+
+	// Wait for the hook to finish, killing it after the timeout.
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err = <-done:
+	case <-time.After(Timeout):
+		kill(cmd)
+		<-done
+		err = errors.New("timed out after " + Timeout.String())
+	}
+
+	writeErr := writeRecords(out, name, event, logs.Stdout, stdout.Bytes())
+	if writeErr == nil {
+		writeErr = writeRecords(out, name, event, logs.Stderr, stderr.Bytes())
+	}
+
+	if err != nil {
+		return errors.Newf(errors.ErrHookTmpl, event, name, err)
+	}
+
+	return writeErr
+}
+
+// writeRecords writes the output of a hook as log records, with the event
+// in each message.
+func writeRecords(out io.Writer, name, event, stream string, data []byte) error {
+	for _, record := range logs.NewRecords(name, stream, data, time.Now()) {
+		record.Message = "(" + event + " hook) " + record.Message
+
+		line, err := record.Marshal()
+		if err != nil {
+			return err
+		}
+
+		_, err = out.Write(append(line, '\n'))
+		if err != nil {
+			return errors.NewStackError(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2013-2014 Bowery, Inc.
+package hooks
+
+import (
+	"bufio"
+	"bytes"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/Bowery/bowery/db"
+	"github.com/Bowery/bowery/logs"
+)
+
+// hooks.go
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Hooks in the test use sh.")
+	}
+
+	service := &db.Service{Hooks: map[string]string{
+		Sync: "echo $BOWERY_SERVICE $BOWERY_EVENT; echo failed >&2",
+	}}
+	var out bytes.Buffer
+
+	err := Run("web", service, Sync, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := make([]*logs.Record, 0)
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		records = append(records, logs.ParseRecord(scanner.Bytes()))
+	}
+	if len(records) != 2 {
+		t.Fatal("Run should write 2 records, got", len(records))
+	}
+	if records[0].Service != "web" || records[0].Stream != logs.Stdout ||
+		records[0].Message != "(sync hook) web sync" {
+		t.Error("Run wrote an invalid stdout record", records[0])
+	}
+	if records[1].Stream != logs.Stderr || records[1].Message != "(sync hook) failed" {
+		t.Error("Run wrote an invalid stderr record", records[1])
+	}
+
+	// No hook for the event does nothing.
+	out.Reset()
+	err = Run("web", service, Restart, &out)
+	if err != nil || out.Len() != 0 {
+		t.Error("Run should do nothing without a hook", err)
+	}
+}
+
+func TestRunFailed(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Hooks in the test use sh.")
+	}
+	var out bytes.Buffer
+
+	service := &db.Service{Hooks: map[string]string{Restart: "exit 3"}}
+	err := Run("web", service, Restart, &out)
+	if err == nil {
+		t.Error("Run should fail if the hook exits with an error.")
+	}
+
+	timeout := Timeout
+	Timeout = 100 * time.Millisecond
+	defer func() { Timeout = timeout }()
+
+	service.Hooks[Restart] = "sleep 5"
+	start := time.Now()
+	err = Run("web", service, Restart, &out)
+	if err == nil || time.Since(start) > 2*time.Second {
+		t.Error("Run should kill hooks after the timeout.")
+	}
+}
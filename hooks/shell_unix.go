@@ -0,0 +1,23 @@
+// +build linux darwin
+
+// Copyright 2013-2014 Bowery, Inc.
+package hooks
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// shell creates a command that runs a command string with sh, in its own
+// process group so the commands it starts can be killed with it.
+func shell(command string) *exec.Cmd {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	return cmd
+}
+
+// kill kills the commands process group.
+func kill(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
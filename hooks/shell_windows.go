@@ -0,0 +1,16 @@
+// Copyright 2013-2014 Bowery, Inc.
+package hooks
+
+import (
+	"os/exec"
+)
+
+// shell creates a command that runs a command string with cmd.
+func shell(command string) *exec.Cmd {
+	return exec.Command("cmd", "/C", command)
+}
+
+// kill kills the command.
+func kill(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,125 @@
+// Copyright 2013-2014 Bowery, Inc.
+package logs
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Bowery/bowery/errors"
+)
+
+// Redacted replaces secret values in exports.
+const Redacted = "[redacted]"
+
+// secretKeys are parts of keys whose values are redacted.
+var secretKeys = []string{"token", "password", "secret", "key"}
+
+// Export writes a gzipped tar archive to path, containing the given files
+// by name.
+func Export(path string, files map[string][]byte) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+	defer file.Close()
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		err = tarWriter.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(files[name])),
+			ModTime: time.Now(),
+		})
+		if err == nil {
+			_, err = tarWriter.Write(files[name])
+		}
+		if err != nil {
+			return errors.NewStackError(err)
+		}
+	}
+
+	err = tarWriter.Close()
+	if err == nil {
+		err = gzipWriter.Close()
+	}
+	if err == nil {
+		err = file.Close()
+	}
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+
+	return nil
+}
+
+// Redact encodes v as indented JSON, replacing the values of secret keys
+// like tokens and passwords, and the values of environment variables.
+func Redact(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.NewStackError(err)
+	}
+
+	var generic interface{}
+	err = json.Unmarshal(data, &generic)
+	if err != nil {
+		return nil, errors.NewStackError(err)
+	}
+
+	data, err = json.MarshalIndent(redact(generic, false), "", "  ")
+	if err != nil {
+		return nil, errors.NewStackError(err)
+	}
+
+	return data, nil
+}
+
+// redact replaces the secret values in a decoded JSON value, if secret is
+// true all of its strings are replaced.
+func redact(v interface{}, secret bool) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for key, item := range val {
+			val[key] = redact(item, secret || isSecretKey(key))
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = redact(item, secret)
+		}
+	case string:
+		if secret && val != "" {
+			return Redacted
+		}
+	}
+
+	return v
+}
+
+// isSecretKey checks if the values for a key should be redacted.
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	if key == "env" {
+		return true
+	}
+
+	for _, secretKey := range secretKeys {
+		if strings.Contains(key, secretKey) {
+			return true
+		}
+	}
+
+	return false
+}
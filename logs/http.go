@@ -0,0 +1,136 @@
+// Copyright 2013-2014 Bowery, Inc.
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Bowery/bowery/api"
+	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/bowery/responses"
+)
+
+// HTTPSource receives records streamed from the api as Server-Sent Events,
+// where the data of each event is a record. It works through proxies and
+// firewalls that block Redis.
+type HTTPSource struct {
+	records chan *Record
+	errors  chan error
+	done    chan struct{}
+	body    io.ReadCloser
+}
+
+// DialHTTP requests the applications log stream from the api.
+func DialHTTP(appID, token string) (*HTTPSource, error) {
+	endpoint := strings.Replace(api.LogsPath, "{id}", appID, -1)
+	endpoint = strings.Replace(endpoint, "{token}", token, -1)
+
+	req, err := http.NewRequest("GET", api.BasePath+endpoint, nil)
+	if err != nil {
+		return nil, errors.NewStackError(err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.NewStackError(err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		logsRes := new(responses.Res)
+		decoder := json.NewDecoder(res.Body)
+		err = decoder.Decode(logsRes)
+		if err != nil {
+			return nil, errors.NewStackError(err)
+		}
+
+		return nil, errors.NewStackError(logsRes)
+	}
+
+	source := &HTTPSource{
+		records: make(chan *Record),
+		errors:  make(chan error, 1),
+		done:    make(chan struct{}),
+		body:    res.Body,
+	}
+	go source.receive()
+
+	return source, nil
+}
+
+// Records receives the records as they're streamed.
+func (source *HTTPSource) Records() <-chan *Record {
+	return source.records
+}
+
+// Errors receives an error if the stream fails or ends.
+func (source *HTTPSource) Errors() <-chan error {
+	return source.errors
+}
+
+// Close closes the stream, records that haven't been received are dropped.
+func (source *HTTPSource) Close() error {
+	close(source.done)
+	return source.body.Close()
+}
+
+// receive sends records for received events until the stream fails or the
+// source is closed.
+func (source *HTTPSource) receive() {
+	err := readEvents(source.body, func(data []byte) {
+		for _, record := range parseMessage(data, time.Now()) {
+			select {
+			case source.records <- record:
+			case <-source.done:
+				return
+			}
+		}
+	})
+	if err == nil {
+		err = io.ErrUnexpectedEOF
+	}
+
+	select {
+	case source.errors <- errors.NewStackError(err):
+	case <-source.done:
+	}
+}
+
+// readEvents reads Server-Sent Events from r calling fn with the data of
+// each event, until r ends.
+func readEvents(r io.Reader, fn func([]byte)) error {
+	var data bytes.Buffer
+	reader := bufio.NewReader(r)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			// An event that isn't finished when the stream ends is dropped.
+			if err == io.EOF {
+				return nil
+			}
+
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		// A blank line dispatches the event, lines with a colon first are
+		// comments used to keep the connection open.
+		switch {
+		case line == "":
+			if data.Len() > 0 {
+				fn(bytes.TrimSuffix(data.Bytes(), []byte("\n")))
+				data.Reset()
+			}
+		case line == "data" || strings.HasPrefix(line, "data:"):
+			value := strings.TrimPrefix(strings.TrimPrefix(line, "data"), ":")
+			data.WriteString(strings.TrimPrefix(value, " ") + "\n")
+		}
+	}
+}
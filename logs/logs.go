@@ -0,0 +1,129 @@
+// Copyright 2013-2014 Bowery, Inc.
+// Package logs contains routines to receive and store the output of an
+// applications services.
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Bowery/bowery/errors"
+)
+
+// Streams a record can come from.
+const (
+	Stdout = "stdout"
+	Stderr = "stderr"
+)
+
+// Record is a single line of output from a service, stored as a line of
+// JSON in output.log.
+type Record struct {
+	Time    time.Time `json:"time"`
+	Service string    `json:"service,omitempty"`
+	Stream  string    `json:"stream"`
+	Message string    `json:"message"`
+}
+
+// NewRecords splits the data a service sent into records for each line, a
+// trailing newline doesn't create an empty record.
+func NewRecords(service, stream string, data []byte, t time.Time) []*Record {
+	data = bytes.TrimRight(data, "\r\n")
+	if len(data) <= 0 {
+		return nil
+	}
+
+	lines := strings.Split(string(data), "\n")
+	records := make([]*Record, len(lines))
+	for i, line := range lines {
+		records[i] = &Record{
+			Time:    t,
+			Service: service,
+			Stream:  stream,
+			Message: strings.TrimRight(line, "\r"),
+		}
+	}
+
+	return records
+}
+
+// ParseRecord parses a line of JSON written by Marshal. Lines that aren't
+// records, e.g. output from older versions, are returned as the message with
+// a zero time and no service.
+func ParseRecord(line []byte) *Record {
+	record := new(Record)
+	err := json.Unmarshal(line, record)
+	if err != nil || (record.Stream == "" && record.Service == "") {
+		return &Record{Stream: Stdout, Message: string(line)}
+	}
+
+	// Records without a stream are output.
+	if record.Stream == "" {
+		record.Stream = Stdout
+	}
+
+	return record
+}
+
+// parseMessage gets the records for each line of a message from a log
+// source. Each line is a record with the service and stream it came from,
+// lines that aren't have no service and lines without a time get t.
+func parseMessage(data []byte, t time.Time) []*Record {
+	data = bytes.TrimRight(data, "\r\n")
+	if len(data) <= 0 {
+		return nil
+	}
+
+	lines := bytes.Split(data, []byte("\n"))
+	records := make([]*Record, len(lines))
+	for i, line := range lines {
+		records[i] = ParseRecord(bytes.TrimRight(line, "\r"))
+		if records[i].Time.IsZero() {
+			records[i].Time = t
+		}
+	}
+
+	return records
+}
+
+// ReadRecords reads the records in a log file, calling fn for each. A file
+// that was removed by a rotation is skipped.
+func ReadRecords(path string, fn func(*Record) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return errors.NewStackError(err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		err = fn(ParseRecord(scanner.Bytes()))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return errors.NewStackError(err)
+	}
+
+	return nil
+}
+
+// Marshal encodes the record as a line of JSON, without the newline.
+func (record *Record) Marshal() ([]byte, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, errors.NewStackError(err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,395 @@
+// Copyright 2013-2014 Bowery, Inc.
+package logs
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// logs.go
+func TestNewRecords(t *testing.T) {
+	now := time.Now()
+	records := NewRecords("web", Stderr, []byte("listening\r\nGET /\n"), now)
+	if len(records) != 2 {
+		t.Fatal("NewRecords should split data into 2 records, got", len(records))
+	}
+	if records[0].Message != "listening" || records[1].Message != "GET /" ||
+		records[1].Service != "web" || records[1].Stream != Stderr {
+		t.Error("NewRecords created invalid records", records[0], records[1])
+	}
+
+	if len(NewRecords("web", Stdout, []byte("\n"), now)) != 0 {
+		t.Error("NewRecords should give no records for empty data.")
+	}
+}
+
+// logs.go
+func TestParseRecord(t *testing.T) {
+	record := &Record{
+		Time:    time.Date(2014, 6, 1, 15, 4, 5, 0, time.UTC),
+		Service: "web",
+		Stream:  Stdout,
+		Message: "GET / 200 ok",
+	}
+
+	data, err := record.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed := ParseRecord(data)
+	if !parsed.Time.Equal(record.Time) || parsed.Service != record.Service ||
+		parsed.Stream != record.Stream || parsed.Message != record.Message {
+		t.Error("ParseRecord failed to parse", string(data), "got", parsed)
+	}
+
+	parsed = ParseRecord([]byte("old output"))
+	if !parsed.Time.IsZero() || parsed.Stream != Stdout || parsed.Message != "old output" {
+		t.Error("ParseRecord should give the message for lines that aren't records, got", parsed)
+	}
+}
+
+// logs.go
+func TestParseMessage(t *testing.T) {
+	now := time.Now()
+	data := []byte(`{"time":"2014-06-01T15:04:05Z","service":"web","stream":"stdout","message":"listening"}` +
+		"\n" + `{"service":"web","stream":"stderr","message":"failed"}` + "\nplain output\r\n")
+
+	records := parseMessage(data, now)
+	if len(records) != 3 {
+		t.Fatal("parseMessage should give 3 records, got", len(records))
+	}
+	if records[0].Service != "web" || records[0].Message != "listening" || records[0].Time.Equal(now) {
+		t.Error("parseMessage should parse records", records[0])
+	}
+	if records[1].Stream != Stderr || !records[1].Time.Equal(now) {
+		t.Error("parseMessage should keep the stream and add the time", records[1])
+	}
+	if records[2].Service != "" || records[2].Message != "plain output" || records[2].Stream != Stdout {
+		t.Error("parseMessage should give plain lines as stdout", records[2])
+	}
+}
+
+// writer.go
+func TestWriterRotate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bowery-logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "output.log")
+
+	writer, err := NewWriter(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err = writer.Write([]byte(line))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = writer.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := Files(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 || files[0] != path+".2" || files[2] != path {
+		t.Fatal("Files returned invalid files", files)
+	}
+
+	contents := make([]string, len(files))
+	for i, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		contents[i] = string(data)
+	}
+	if strings.Join(contents, "") != "second\nthird\nfourth\n" {
+		t.Error("Writer kept invalid contents", contents)
+	}
+}
+
+// writer.go
+func TestWriterRotateFailed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bowery-logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "output.log")
+
+	// A directory that isn't empty can't be replaced by the rotated file.
+	err = os.MkdirAll(filepath.Join(path+".1", "keep"), os.ModePerm|os.ModeDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer, err := NewWriter(path, 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+
+	_, err = writer.Write([]byte("first line\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = writer.Write([]byte("second\n")); err == nil {
+		t.Error("Write should fail if the file can't be rotated.")
+	}
+
+	if _, err = writer.file.Stat(); err != nil {
+		t.Fatal("Writer should reopen the file after a failed rotation", err)
+	}
+
+	// Once the files can be moved writes succeed again.
+	err = os.RemoveAll(path + ".1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = writer.Write([]byte("third\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path + ".1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "first line\n" {
+		t.Error("Writer should keep the file after a failed rotation", string(data))
+	}
+}
+
+// writer.go
+func TestWriting(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bowery-logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "output.log")
+
+	writer, err := NewWriter(path, 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Writing(path) {
+		t.Error("Writing should be true while the writer is open.")
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Writing(path) {
+		t.Error("Writing should be false once the writer is closed.")
+	}
+
+	// A writer that exited without closing isn't writing.
+	err = ioutil.WriteFile(path+".pid", []byte("999999999"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Writing(path) {
+		t.Error("Writing should be false for a process that isn't running.")
+	}
+}
+
+// tail.go
+func TestFollowRotated(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bowery-logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "output.log")
+
+	interval := PollInterval
+	PollInterval = 5 * time.Millisecond
+	defer func() { PollInterval = interval }()
+
+	writer, err := NewWriter(path, 1000, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+
+	tail, err := Follow(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tail.Close()
+
+	// Write enough lines to rotate many times while following.
+	count := 100
+	go func() {
+		for i := 0; i < count; i++ {
+			record := &Record{Time: time.Now(), Service: "web", Stream: Stdout, Message: strconv.Itoa(i)}
+			data, _ := record.Marshal()
+			writer.Write(append(data, '\n'))
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	for i := 0; i < count; i++ {
+		select {
+		case record := <-tail.Records():
+			if record.Message != strconv.Itoa(i) {
+				t.Fatal("Tail should give line", i, "got", record.Message)
+			}
+		case err = <-tail.Errors():
+			t.Fatal(err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Tail stopped after", i, "lines")
+		}
+	}
+
+	files, err := Files(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) < 2 {
+		t.Error("Writer should have rotated the file, got", files)
+	}
+}
+
+// http.go
+func TestReadEvents(t *testing.T) {
+	stream := ": keep-alive\n\n" +
+		"data: {\"message\":\"first\"}\n\n" +
+		"event: record\r\ndata:one\r\ndata: two\r\n\r\n" +
+		"data: unfinished\n"
+
+	events := make([]string, 0)
+	err := readEvents(strings.NewReader(stream), func(data []byte) {
+		events = append(events, string(data))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{`{"message":"first"}`, "one\ntwo"}
+	if !reflect.DeepEqual(events, expected) {
+		t.Error("readEvents read invalid events", events)
+	}
+}
+
+// http.go
+func TestHTTPSourceClose(t *testing.T) {
+	reader, writer := io.Pipe()
+	source := &HTTPSource{
+		records: make(chan *Record),
+		errors:  make(chan error, 1),
+		done:    make(chan struct{}),
+		body:    reader,
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		source.receive()
+		close(finished)
+	}()
+
+	// The record is never received, so receive is blocked sending it.
+	go writer.Write([]byte("data: first\n\n"))
+	time.Sleep(10 * time.Millisecond)
+	source.Close()
+
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Error("receive should finish once the source is closed.")
+	}
+}
+
+// source.go
+func TestOpenInvalidSource(t *testing.T) {
+	source, err := Open("carrier-pigeon", "abc", "token")
+	if err == nil || source != nil {
+		t.Error("Open should fail for an invalid source name.")
+	}
+}
+
+// export.go
+func TestRedact(t *testing.T) {
+	data, err := Redact(map[string]interface{}{
+		"token": "abc",
+		"app":   map[string]interface{}{"name": "blog", "apiKey": "def"},
+		"config": map[string]interface{}{
+			"web": map[string]interface{}{"env": map[string]string{"DATABASE_URL": "postgres://secret"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	str := string(data)
+	for _, secret := range []string{"abc", "def", "postgres://secret"} {
+		if strings.Contains(str, secret) {
+			t.Error("Redact didn't remove", secret, "got", str)
+		}
+	}
+	if !strings.Contains(str, "blog") || !strings.Contains(str, "DATABASE_URL") {
+		t.Error("Redact removed values that aren't secret, got", str)
+	}
+}
+
+// export.go
+func TestExport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bowery-logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "logs.tar.gz")
+
+	err = Export(path, map[string][]byte{"output.log": []byte("line\n"), "state.json": []byte("{}")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tarReader := tar.NewReader(gzipReader)
+
+	names := make([]string, 0)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		names = append(names, header.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"output.log", "state.json"}) {
+		t.Error("Export wrote invalid files", names)
+	}
+}
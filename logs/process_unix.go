@@ -0,0 +1,19 @@
+// +build linux darwin
+
+// Copyright 2013-2014 Bowery, Inc.
+package logs
+
+import (
+	"os"
+	"syscall"
+)
+
+// processRunning checks if the process with the pid is running.
+func processRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	return process.Signal(syscall.Signal(0)) == nil
+}
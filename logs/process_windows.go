@@ -0,0 +1,18 @@
+// Copyright 2013-2014 Bowery, Inc.
+package logs
+
+import (
+	"os"
+)
+
+// processRunning checks if the process with the pid is running, finding it
+// fails if it isn't.
+func processRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	process.Release()
+
+	return true
+}
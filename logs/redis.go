@@ -0,0 +1,85 @@
+// Copyright 2013-2014 Bowery, Inc.
+package logs
+
+import (
+	"time"
+
+	"github.com/Bowery/bowery/errors"
+	"github.com/garyburd/redigo/redis"
+)
+
+// RedisSource receives records from the Redis logs channel of an
+// application, "logs:<appID>". Messages are lines of output, a line that's a
+// record (see Marshal) carries the service it came from.
+type RedisSource struct {
+	records chan *Record
+	errors  chan error
+	done    chan struct{}
+	pubsub  redis.PubSubConn
+}
+
+// DialRedis connects to Redis at addr and subscribes to the applications
+// logs channel.
+func DialRedis(addr, appID string) (*RedisSource, error) {
+	conn, err := redis.DialTimeout("tcp", addr, DialTimeout, 0, 0)
+	if err != nil {
+		return nil, errors.NewStackError(err)
+	}
+	pubsub := redis.PubSubConn{Conn: conn}
+
+	err = pubsub.Subscribe("logs:" + appID)
+	if err != nil {
+		conn.Close()
+		return nil, errors.NewStackError(err)
+	}
+
+	source := &RedisSource{
+		records: make(chan *Record),
+		errors:  make(chan error, 1),
+		done:    make(chan struct{}),
+		pubsub:  pubsub,
+	}
+	go source.receive()
+
+	return source, nil
+}
+
+// Records receives the records as they're published.
+func (source *RedisSource) Records() <-chan *Record {
+	return source.records
+}
+
+// Errors receives an error if the connection fails.
+func (source *RedisSource) Errors() <-chan error {
+	return source.errors
+}
+
+// Close unsubscribes and closes the connection, records that haven't been
+// received are dropped.
+func (source *RedisSource) Close() error {
+	close(source.done)
+	return source.pubsub.Close()
+}
+
+// receive sends records for received messages until the connection fails or
+// the source is closed.
+func (source *RedisSource) receive() {
+	for {
+		switch res := source.pubsub.Receive().(type) {
+		case redis.Message:
+			for _, record := range parseMessage(res.Data, time.Now()) {
+				select {
+				case source.records <- record:
+				case <-source.done:
+					return
+				}
+			}
+		case error:
+			select {
+			case source.errors <- errors.NewStackError(res):
+			case <-source.done:
+			}
+			return
+		}
+	}
+}
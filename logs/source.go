@@ -0,0 +1,67 @@
+// Copyright 2013-2014 Bowery, Inc.
+package logs
+
+import (
+	"time"
+
+	"github.com/Bowery/bowery/api"
+	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/gopackages/log"
+)
+
+// Names of the sources, set with `bowery config logsource`.
+const (
+	SourceAuto  = "auto"
+	SourceRedis = "redis"
+	SourceHTTP  = "http"
+)
+
+// DialTimeout is how long to wait for a source to connect.
+var DialTimeout = 5 * time.Second
+
+// Source streams the log records of an application.
+type Source interface {
+	// Records receives the records as they're streamed.
+	Records() <-chan *Record
+
+	// Errors receives an error if the stream fails, no records are received
+	// after.
+	Errors() <-chan error
+
+	// Close stops the stream.
+	Close() error
+}
+
+// Open opens the source with the given name for an application. Auto, or an
+// empty name, uses Redis if it can be reached and otherwise HTTP.
+func Open(name, appID, token string) (Source, error) {
+	if name == SourceAuto || name == "" {
+		source, err := Open(SourceRedis, appID, token)
+		if err == nil {
+			return source, nil
+		}
+		log.Debug("Couldn't connect to Redis", api.RedisPath, "falling back to HTTP:", err)
+
+		name = SourceHTTP
+	}
+
+	// Nil is returned explicitly on errors so the Source isn't a nil pointer.
+	switch name {
+	case SourceRedis:
+		source, err := DialRedis(api.RedisPath, appID)
+		if err != nil {
+			return nil, err
+		}
+
+		return source, nil
+	case SourceHTTP:
+		source, err := DialHTTP(appID, token)
+		if err != nil {
+			return nil, err
+		}
+
+		return source, nil
+	}
+
+	return nil, errors.Newf(errors.ErrInvalidConfigValueTmpl, name, "logsource")
+}
@@ -0,0 +1,272 @@
+// Copyright 2013-2014 Bowery, Inc.
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Bowery/bowery/errors"
+)
+
+// PollInterval is how often a followed log file is checked for new lines.
+var PollInterval = 250 * time.Millisecond
+
+// Tail follows a log file written by a Writer from the start, it's a Source
+// for the records in the file. When the file is rotated the rest of it is
+// read, then the files written after it.
+type Tail struct {
+	Path    string
+	records chan *Record
+	errors  chan error
+	done    chan struct{}
+	file    *os.File
+	reader  *bufio.Reader
+	partial []byte
+}
+
+// Follow opens the log file at path and follows it.
+func Follow(path string) (*Tail, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.NewStackError(err)
+	}
+
+	tail := &Tail{
+		Path:    path,
+		records: make(chan *Record),
+		errors:  make(chan error, 1),
+		done:    make(chan struct{}),
+		file:    file,
+		reader:  bufio.NewReader(file),
+	}
+	go tail.follow()
+
+	return tail, nil
+}
+
+// Records receives the records as they're written.
+func (tail *Tail) Records() <-chan *Record {
+	return tail.records
+}
+
+// Errors receives an error if reading fails.
+func (tail *Tail) Errors() <-chan error {
+	return tail.errors
+}
+
+// Close stops following the file.
+func (tail *Tail) Close() error {
+	close(tail.done)
+	return nil
+}
+
+// follow sends the records in the file until closed, switching to the next
+// files when it's rotated.
+func (tail *Tail) follow() {
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+	defer func() {
+		tail.file.Close()
+	}()
+
+	for {
+		ok, err := tail.read()
+		if !ok {
+			return
+		}
+
+		if err == nil {
+			ok, err = tail.rotate()
+			if !ok {
+				return
+			}
+		}
+
+		if err != nil {
+			select {
+			case tail.errors <- err:
+			case <-tail.done:
+			}
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-tail.done:
+			return
+		}
+	}
+}
+
+// read sends the complete lines that can be read from the current file,
+// false is returned if the tail was closed.
+func (tail *Tail) read() (bool, error) {
+	for {
+		line, err := tail.reader.ReadBytes('\n')
+		tail.partial = append(tail.partial, line...)
+		if err != nil {
+			if err == io.EOF {
+				return true, nil
+			}
+
+			return true, errors.NewStackError(err)
+		}
+
+		ok := tail.send()
+		if !ok {
+			return false, nil
+		}
+	}
+}
+
+// send sends the line read so far as a record, empty lines are skipped.
+func (tail *Tail) send() bool {
+	line := bytes.TrimRight(tail.partial, "\r\n")
+	tail.partial = nil
+	if len(line) <= 0 {
+		return true
+	}
+
+	select {
+	case tail.records <- ParseRecord(line):
+		return true
+	case <-tail.done:
+		return false
+	}
+}
+
+// rotate checks if the current file was rotated, and if so reads the files
+// written after it and continues with the newest. False is returned if the
+// tail was closed.
+func (tail *Tail) rotate() (bool, error) {
+	info, err := os.Stat(tail.Path)
+	if err != nil {
+		// The new file hasn't been created yet.
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+
+		return true, errors.NewStackError(err)
+	}
+
+	current, err := tail.file.Stat()
+	if err != nil {
+		return true, errors.NewStackError(err)
+	}
+	if os.SameFile(info, current) {
+		return true, nil
+	}
+
+	files, err := tail.next(current)
+	if err != nil {
+		return true, err
+	}
+	if len(files) <= 0 {
+		return true, nil
+	}
+
+	// The current file is complete, so a partial line is the last line.
+	for i, file := range files {
+		if len(tail.partial) > 0 && !tail.send() {
+			closeFiles(files[i:])
+			return false, nil
+		}
+
+		tail.file.Close()
+		tail.file = file
+		tail.reader = bufio.NewReader(file)
+		if i == len(files)-1 {
+			break
+		}
+
+		ok, err := tail.read()
+		if !ok || err != nil {
+			closeFiles(files[i+1:])
+			return ok, err
+		}
+	}
+
+	return true, nil
+}
+
+// next opens the files written after the current file, oldest first. If the
+// current file was removed all the files are newer.
+func (tail *Tail) next(current os.FileInfo) ([]*os.File, error) {
+	for {
+		files, infos, err := tail.open()
+		if err != nil {
+			return nil, err
+		}
+		if files == nil {
+			continue // Rotated while opening.
+		}
+
+		for i, info := range infos {
+			if os.SameFile(info, current) {
+				closeFiles(files[:i+1])
+				return files[i+1:], nil
+			}
+		}
+
+		return files, nil
+	}
+}
+
+// open opens the log file and its rotated files, oldest first. The files
+// are renamed on rotation, so nil is returned if the names changed while
+// opening them.
+func (tail *Tail) open() ([]*os.File, []os.FileInfo, error) {
+	paths, err := Files(tail.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	files := make([]*os.File, 0, len(paths))
+	infos := make([]os.FileInfo, 0, len(paths))
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err == nil {
+			files = append(files, file)
+
+			var info os.FileInfo
+			info, err = file.Stat()
+			infos = append(infos, info)
+		}
+		if err != nil {
+			closeFiles(files)
+			if os.IsNotExist(err) {
+				return nil, nil, nil
+			}
+
+			return nil, nil, errors.NewStackError(err)
+		}
+	}
+
+	// Make sure each name still points to the file opened for it.
+	check, err := Files(tail.Path)
+	if err != nil {
+		closeFiles(files)
+		return nil, nil, err
+	}
+	changed := len(check) != len(paths)
+	for i := 0; !changed && i < len(paths); i++ {
+		info, err := os.Stat(paths[i])
+		changed = err != nil || check[i] != paths[i] || !os.SameFile(info, infos[i])
+	}
+	if changed {
+		closeFiles(files)
+		return nil, nil, nil
+	}
+
+	return files, infos, nil
+}
+
+// closeFiles closes the files.
+func closeFiles(files []*os.File) {
+	for _, file := range files {
+		file.Close()
+	}
+}
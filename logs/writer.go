@@ -0,0 +1,235 @@
+// Copyright 2013-2014 Bowery, Inc.
+package logs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bowery/bowery/errors"
+)
+
+// Defaults for rotating logs.
+var (
+	DefaultMaxSize  int64 = 10 * 1024 * 1024
+	DefaultMaxFiles       = 3
+	SyncInterval          = time.Second
+)
+
+// Writer appends to a log file, rotating it once it reaches MaxSize. The
+// rotated files are named <path>.1 (newest) to <path>.<MaxFiles>, older
+// files are removed. Writes are synced to the fs in batches every
+// SyncInterval instead of after each write. While open the writers pid is
+// kept in <path>.pid, see Writing.
+type Writer struct {
+	Path     string
+	MaxSize  int64
+	MaxFiles int
+	file     *os.File
+	size     int64
+	dirty    bool
+	done     chan struct{}
+	mutex    sync.Mutex
+}
+
+// NewWriter opens the log file at path for appending, creating it if needed.
+func NewWriter(path string, maxSize int64, maxFiles int) (*Writer, error) {
+	writer := &Writer{
+		Path:     path,
+		MaxSize:  maxSize,
+		MaxFiles: maxFiles,
+		done:     make(chan struct{}),
+	}
+
+	err := writer.open()
+	if err != nil {
+		return nil, err
+	}
+
+	err = ioutil.WriteFile(path+".pid", []byte(strconv.Itoa(os.Getpid())), 0644)
+	if err != nil {
+		writer.file.Close()
+		return nil, errors.NewStackError(err)
+	}
+	go writer.syncLoop()
+
+	return writer, nil
+}
+
+// Write writes the given buffer, rotating first if it would make the file
+// larger than MaxSize.
+func (writer *Writer) Write(b []byte) (int, error) {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	if writer.size > 0 && writer.size+int64(len(b)) > writer.MaxSize {
+		err := writer.rotate()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := writer.file.Write(b)
+	writer.size += int64(n)
+	writer.dirty = true
+	if err != nil {
+		return n, errors.NewStackError(err)
+	}
+
+	return n, nil
+}
+
+// Close syncs and closes the writer after any writes have completed.
+func (writer *Writer) Close() error {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	close(writer.done)
+	os.Remove(writer.Path + ".pid")
+	writer.file.Sync()
+	return writer.file.Close()
+}
+
+// Writing checks if a running process has a writer open for the log file at
+// path.
+func Writing(path string) bool {
+	data, err := ioutil.ReadFile(path + ".pid")
+	if err != nil {
+		return false
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	return err == nil && pid > 0 && processRunning(pid)
+}
+
+// open opens the file at Path and gets its size, creating its directory if
+// needed.
+func (writer *Writer) open() error {
+	err := os.MkdirAll(filepath.Dir(writer.Path), os.ModePerm|os.ModeDir)
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+
+	file, err := os.OpenFile(writer.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return errors.NewStackError(err)
+	}
+
+	writer.file = file
+	writer.size = info.Size()
+	return nil
+}
+
+// rotate shifts the rotated files up, removing the oldest, and moves the
+// current file to <path>.1. If the files can't be moved the current file is
+// reopened so later writes still succeed.
+func (writer *Writer) rotate() error {
+	err := writer.file.Sync()
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+	writer.dirty = false
+
+	err = writer.file.Close()
+	if err == nil {
+		err = writer.shift()
+	} else {
+		err = errors.NewStackError(err)
+	}
+	if err != nil {
+		openErr := writer.open()
+		if openErr != nil {
+			return openErr
+		}
+
+		return err
+	}
+
+	return writer.open()
+}
+
+// shift moves the current and rotated files up.
+func (writer *Writer) shift() error {
+	os.Remove(writer.Path + "." + strconv.Itoa(writer.MaxFiles))
+	for i := writer.MaxFiles - 1; i > 0; i-- {
+		name := writer.Path + "." + strconv.Itoa(i)
+		err := os.Rename(name, writer.Path+"."+strconv.Itoa(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return errors.NewStackError(err)
+		}
+	}
+
+	// Keep nothing but the current file if no rotated files are wanted.
+	var err error
+	if writer.MaxFiles > 0 {
+		err = os.Rename(writer.Path, writer.Path+".1")
+	} else {
+		err = os.Remove(writer.Path)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return errors.NewStackError(err)
+	}
+
+	return nil
+}
+
+// syncLoop syncs written data to the fs every SyncInterval until closed.
+func (writer *Writer) syncLoop() {
+	ticker := time.NewTicker(SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			writer.mutex.Lock()
+			if writer.dirty {
+				writer.file.Sync()
+				writer.dirty = false
+			}
+			writer.mutex.Unlock()
+		case <-writer.done:
+			return
+		}
+	}
+}
+
+// Files gets the log file at path and its rotated files that exist, oldest
+// first.
+func Files(path string) ([]string, error) {
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return nil, errors.NewStackError(err)
+	}
+
+	rotated := make([]int, 0, len(matches))
+	for _, match := range matches {
+		i, err := strconv.Atoi(strings.TrimPrefix(match, path+"."))
+		if err == nil && i > 0 {
+			rotated = append(rotated, i)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(rotated)))
+
+	files := make([]string, 0, len(rotated)+1)
+	for _, i := range rotated {
+		files = append(files, path+"."+strconv.Itoa(i))
+	}
+
+	_, err = os.Stat(path)
+	if err == nil {
+		files = append(files, path)
+	}
+
+	return files, nil
+}
@@ -12,6 +12,17 @@ import (
 	"github.com/Bowery/gopackages/log"
 )
 
+// NonInteractive answers prompts without reading stdin, questions are
+// answered yes and input is read from the environment variable named by
+// EnvName.
+var NonInteractive = false
+
+// EnvName gets the environment variable used for a prompt in non-interactive
+// mode, e.g. BOWERY_REMOTE_PATH for "Remote Path".
+func EnvName(prefix string) string {
+	return "BOWERY_" + strings.ToUpper(strings.Join(strings.Fields(prefix), "_"))
+}
+
 // Basic gets input and if required tests to ensure input was given.
 func Basic(prefix string, required bool) (string, error) {
 	return Custom(prefix, func(input string) (string, bool) {
@@ -26,6 +37,15 @@ func Basic(prefix string, required bool) (string, error) {
 
 // BasicDefault gets input and if empty uses the given default.
 func BasicDefault(prefix, def string) (string, error) {
+	if NonInteractive {
+		input := os.Getenv(EnvName(prefix))
+		if input == "" {
+			input = def
+		}
+
+		return input, nil
+	}
+
 	return Custom(prefix+"(Default: "+def+")", func(input string) (string, bool) {
 		if input == "" {
 			input = def
@@ -38,6 +58,11 @@ func BasicDefault(prefix, def string) (string, error) {
 // Ask gets input and checks if it's truthy or not, and returns that
 // in a boolean fashion.
 func Ask(question string) (bool, error) {
+	if NonInteractive {
+		log.Debug(question, "answered yes")
+		return true, nil
+	}
+
 	line, err := Custom(question+"?(y/n)", func(input string) (string, bool) {
 		if input == "" {
 			log.Fprintln(os.Stderr, "red", "Answer", errors.ErrEmpty)
@@ -67,6 +92,17 @@ func Custom(prefix string, test func(string) (string, bool)) (string, error) {
 	line := ""
 	ok := false
 
+	// Without a terminal the input can only be tested once.
+	if NonInteractive {
+		env := EnvName(prefix)
+		line, ok = test(os.Getenv(env))
+		if !ok {
+			return "", errors.Newf(errors.ErrNoAnswerTmpl, prefix, env)
+		}
+
+		return line, nil
+	}
+
 	term, err := NewTerminal()
 	if err != nil {
 		return "", err
@@ -94,6 +130,16 @@ func Password(prefix string) (string, error) {
 	var err error
 	line := ""
 
+	if NonInteractive {
+		env := EnvName(prefix)
+		line = os.Getenv(env)
+		if line == "" {
+			return "", errors.Newf(errors.ErrNoAnswerTmpl, prefix, env)
+		}
+
+		return line, nil
+	}
+
 	term, err := NewTerminal()
 	if err != nil {
 		return "", err
@@ -0,0 +1,39 @@
+// Copyright 2013-2014 Bowery, Inc.
+package prompt
+
+import (
+	"os"
+	"testing"
+)
+
+// prompt.go
+func TestEnvName(t *testing.T) {
+	if name := EnvName("Remote Path"); name != "BOWERY_REMOTE_PATH" {
+		t.Error("EnvName should be BOWERY_REMOTE_PATH, got", name)
+	}
+}
+
+func TestNonInteractive(t *testing.T) {
+	NonInteractive = true
+	defer func() { NonInteractive = false }()
+	os.Setenv("BOWERY_START_COMMAND", "node app.js")
+	os.Unsetenv("BOWERY_NAME")
+	os.Unsetenv("BOWERY_REMOTE_PATH")
+	defer os.Unsetenv("BOWERY_START_COMMAND")
+
+	if start, err := Basic("Start Command", true); err != nil || start != "node app.js" {
+		t.Error("Basic should read the answer from the environment", start, err)
+	}
+	if _, err := Basic("Name", true); err == nil {
+		t.Error("Basic should fail if a required answer is missing.")
+	}
+	if root, err := BasicDefault("Remote Path", "/application"); err != nil || root != "/application" {
+		t.Error("BasicDefault should use the default if the answer is missing", root, err)
+	}
+	if ok, err := Ask("Are you sure"); err != nil || !ok {
+		t.Error("Ask should answer yes", err)
+	}
+	if _, err := Password("Name"); err == nil {
+		t.Error("Password should fail if the answer is missing.")
+	}
+}
@@ -3,6 +3,7 @@ package responses
 
 import (
 	"strings"
+	"time"
 
 	"github.com/Bowery/bowery/db"
 	"github.com/Bowery/gopackages/schemas"
@@ -67,6 +68,13 @@ type RestartRes struct {
 	Service *schemas.Service `json:"service"`
 }
 
+// ServiceStatusRes contains the status of a service.
+type ServiceStatusRes struct {
+	*Res
+	State       string    `json:"state"`
+	RestartedAt time.Time `json:"restartedAt"`
+}
+
 // isRefusedConn checks if a connection was refused.
 func IsRefusedConn(err error) bool {
 	if err == nil {
@@ -0,0 +1,116 @@
+// Copyright 2013-2014 Bowery, Inc.
+package ssh
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/gopackages/schemas"
+)
+
+// Config creates an OpenSSH config block with a host entry for each of the
+// apps services, aliased as <app>-<service>. The entries use the host keys
+// pinned in KnownHostsPath, so it must be relative to the apps root.
+func Config(app *schemas.Application) (string, error) {
+	var buf bytes.Buffer
+	appName := app.ID
+	if app.Name != "" {
+		appName = app.Name
+	}
+
+	knownHosts, err := filepath.Abs(KnownHostsPath)
+	if err != nil {
+		return "", errors.NewStackError(err)
+	}
+
+	buf.WriteString(configStart(app) + "\n")
+	for _, service := range app.Services {
+		host, port, err := net.SplitHostPort(service.SSHAddr)
+		if err != nil {
+			return "", errors.NewStackError(err)
+		}
+		alias := strings.Replace(appName+"-"+service.Name, " ", "-", -1)
+
+		buf.WriteString("Host " + alias + "\n")
+		buf.WriteString("  HostName " + host + "\n")
+		buf.WriteString("  Port " + port + "\n")
+		buf.WriteString("  User root\n")
+		buf.WriteString("  IdentityFile " + configQuote(KeyPath()) + "\n")
+		buf.WriteString("  IdentitiesOnly yes\n")
+		buf.WriteString("  UserKnownHostsFile " + configQuote(knownHosts) + "\n")
+	}
+	buf.WriteString(configEnd(app) + "\n")
+
+	return buf.String(), nil
+}
+
+// configQuote quotes a path for the config if it has spaces.
+func configQuote(path string) string {
+	if strings.ContainsAny(path, " \t") {
+		return `"` + path + `"`
+	}
+
+	return path
+}
+
+// UpdateConfig writes the config block for the app to the file at path,
+// replacing the apps previous block if it exists.
+func UpdateConfig(path string, app *schemas.Application) error {
+	block, err := Config(app)
+	if err != nil {
+		return err
+	}
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.NewStackError(err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+
+	err = ioutil.WriteFile(path, []byte(replaceConfig(string(contents), app, block)), 0600)
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+
+	return nil
+}
+
+// replaceConfig replaces the apps block in contents, or appends it if there
+// isn't one.
+func replaceConfig(contents string, app *schemas.Application, block string) string {
+	start := strings.Index(contents, configStart(app))
+	end := strings.Index(contents, configEnd(app))
+	if start < 0 || end < start {
+		if contents != "" && !strings.HasSuffix(contents, "\n") {
+			contents += "\n"
+		}
+
+		return contents + block
+	}
+
+	end += len(configEnd(app))
+	if end < len(contents) && contents[end] == '\n' {
+		end++
+	}
+
+	return contents[:start] + block + contents[end:]
+}
+
+// configStart is the line that starts an apps config block.
+func configStart(app *schemas.Application) string {
+	return "# BEGIN bowery " + app.ID
+}
+
+// configEnd is the line that ends an apps config block.
+func configEnd(app *schemas.Application) string {
+	return "# END bowery " + app.ID
+}
@@ -0,0 +1,334 @@
+// Copyright 2013-2014 Bowery, Inc.
+package ssh
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"code.google.com/p/go.crypto/ssh"
+	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/gopackages/schemas"
+)
+
+// Download copies a path on the service to a local path, directories are
+// copied recursively. If the local path is an existing directory the remote
+// path is copied into it. Progress is called for each file copied.
+func Download(service *schemas.Service, remote, local string, progress func(string)) error {
+	client, err := dial(service)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+	defer session.Close()
+	var stderr bytes.Buffer
+	session.Stderr = &stderr
+
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+
+	remote = path.Clean(remote)
+	err = session.Start("tar -czf - -C " + quote(path.Dir(remote)) + " " + quote(path.Base(remote)))
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+
+	// Copy into the local path if it's a directory, otherwise copy to it.
+	dir, base := filepath.Dir(local), filepath.Base(local)
+	info, err := os.Stat(local)
+	if err == nil && info.IsDir() {
+		dir, base = local, path.Base(remote)
+	}
+
+	untarErr := untar(stdout, path.Base(remote), filepath.Join(dir, base), progress)
+	if untarErr != nil {
+		io.Copy(ioutil.Discard, stdout) // Let the remote command finish.
+	}
+
+	err = session.Wait()
+	if err != nil {
+		return copyErr(remote, err, &stderr)
+	}
+
+	return untarErr
+}
+
+// Upload copies a local path to a path on the service, directories are
+// copied recursively. If the remote path is an existing directory the local
+// path is copied into it. Progress is called for each file copied.
+func Upload(service *schemas.Service, local, remote string, progress func(string)) error {
+	_, err := os.Lstat(local)
+	if err != nil {
+		return err
+	}
+
+	client, err := dial(service)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	// Copy into the remote path if it's a directory, otherwise copy to it.
+	remote = path.Clean(remote)
+	dir, base := path.Dir(remote), path.Base(remote)
+	isDir, err := remoteIsDir(client, remote)
+	if err != nil {
+		return err
+	}
+	if isDir {
+		dir, base = remote, filepath.Base(local)
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+	defer session.Close()
+	var stderr bytes.Buffer
+	session.Stderr = &stderr
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+
+	err = session.Start("mkdir -p " + quote(dir) + " && tar -xzf - -C " + quote(dir))
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+
+	tarErr := writeTar(stdin, local, base, progress)
+	stdin.Close()
+
+	err = session.Wait()
+	if err != nil {
+		return copyErr(remote, err, &stderr)
+	}
+
+	return tarErr
+}
+
+// remoteIsDir checks if a remote path is a directory.
+func remoteIsDir(client *ssh.Client, remote string) (bool, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return false, errors.NewStackError(err)
+	}
+	defer session.Close()
+
+	err = session.Run("test -d " + quote(remote))
+	if err != nil {
+		if _, ok := err.(*ssh.ExitError); ok {
+			return false, nil
+		}
+
+		return false, errors.NewStackError(err)
+	}
+
+	return true, nil
+}
+
+// untar extracts a gzipped tar stream whose entries are under base, to dest.
+func untar(r io.Reader, base, dest string, progress func(string)) error {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+	defer gzipReader.Close()
+	tarReader := tar.NewReader(gzipReader)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.NewStackError(err)
+		}
+
+		// Replace base with dest, making sure nothing is written outside it.
+		name := strings.TrimPrefix(path.Clean(header.Name), base)
+		target := filepath.Join(dest, filepath.FromSlash(name))
+		if !inDir(dest, target) {
+			continue
+		}
+
+		// Links could point outside dest, so they're never written through.
+		linkErr := checkLinks(dest, target, header)
+		if linkErr != nil {
+			return linkErr
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, os.FileMode(header.Mode)|os.ModeDir)
+		case tar.TypeSymlink:
+			os.Remove(target)
+			err = os.Symlink(header.Linkname, target)
+		case tar.TypeReg, tar.TypeRegA:
+			err = os.MkdirAll(filepath.Dir(target), os.ModePerm|os.ModeDir)
+			if err != nil {
+				break
+			}
+
+			var file *os.File
+			file, err = os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.FileMode(header.Mode))
+			if err != nil {
+				break
+			}
+
+			_, err = io.Copy(file, tarReader)
+			file.Close()
+			if err == nil && progress != nil {
+				progress(path.Join(path.Base(dest), name))
+			}
+		}
+		if err != nil {
+			return errors.NewStackError(err)
+		}
+	}
+}
+
+// checkLinks makes sure target in dest isn't written through a symlink, and
+// that a symlink entry doesn't point outside dest.
+func checkLinks(dest, target string, header *tar.Header) error {
+	unsafe := errors.Newf(errors.ErrUnsafeLinkTmpl, header.Name)
+
+	if header.Typeflag == tar.TypeSymlink {
+		link := filepath.FromSlash(header.Linkname)
+		if !filepath.IsAbs(link) {
+			link = filepath.Join(filepath.Dir(target), link)
+		}
+		if !inDir(dest, filepath.Clean(link)) {
+			return unsafe
+		}
+
+		// The link itself is replaced, so only its parents are checked.
+		target = filepath.Dir(target)
+	}
+
+	for ; inDir(dest, target) && target != dest; target = filepath.Dir(target) {
+		info, err := os.Lstat(target)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return errors.NewStackError(err)
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			return unsafe
+		}
+	}
+
+	return nil
+}
+
+// inDir checks if the clean name is dir or inside it.
+func inDir(dir, name string) bool {
+	return name == dir || strings.HasPrefix(name, dir+string(filepath.Separator))
+}
+
+// writeTar writes a gzipped tar stream of the local path, with entries
+// under base.
+func writeTar(w io.Writer, local, base string, progress func(string)) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	err := filepath.Walk(local, func(fullPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(local, fullPath)
+		if err != nil {
+			return err
+		}
+		name := path.Join(base, filepath.ToSlash(rel))
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err = os.Readlink(fullPath)
+			if err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = name
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		err = tarWriter.WriteHeader(header)
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+
+		file, err := os.Open(fullPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tarWriter, file)
+		if err == nil && progress != nil {
+			progress(name)
+		}
+
+		return err
+	})
+	if err == nil {
+		err = tarWriter.Close()
+	}
+	if err == nil {
+		err = gzipWriter.Close()
+	}
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+
+	return nil
+}
+
+// copyErr creates an error for a failed remote copy command.
+func copyErr(remote string, err error, stderr *bytes.Buffer) error {
+	if _, ok := err.(*ssh.ExitError); ok {
+		return errors.Newf(errors.ErrCopyTmpl, remote, strings.TrimSpace(stderr.String()))
+	}
+
+	return errors.NewStackError(err)
+}
+
+// QuoteArgs quotes each argument for a remote shell command and joins them,
+// so they're given to the command as is.
+func QuoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = quote(arg)
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// quote quotes a string for use as an argument in a remote shell command.
+func quote(str string) string {
+	return "'" + strings.Replace(str, "'", `'\''`, -1) + "'"
+}
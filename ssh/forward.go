@@ -0,0 +1,218 @@
+// Copyright 2013-2014 Bowery, Inc.
+package ssh
+
+import (
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"code.google.com/p/go.crypto/ssh"
+	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/gopackages/log"
+	"github.com/Bowery/gopackages/schemas"
+)
+
+// PortMap maps a local port to a port on a service.
+type PortMap struct {
+	Local  string
+	Remote string
+}
+
+func (port *PortMap) String() string {
+	return port.Local + ":" + port.Remote
+}
+
+// ParsePortMap parses a port map in the form <local>:<remote>, a single
+// port is used for both.
+func ParsePortMap(str string) (*PortMap, error) {
+	ports := strings.Split(str, ":")
+	if len(ports) > 2 {
+		return nil, errors.Newf(errors.ErrInvalidPortTmpl, str)
+	}
+
+	for _, port := range ports {
+		num, err := strconv.Atoi(port)
+		if err != nil || num <= 0 || num > 65535 {
+			return nil, errors.Newf(errors.ErrInvalidPortTmpl, port)
+		}
+	}
+
+	return &PortMap{Local: ports[0], Remote: ports[len(ports)-1]}, nil
+}
+
+// Forwarder forwards local ports to a service through an ssh connection,
+// reconnecting if the connection drops.
+type Forwarder struct {
+	Service *schemas.Service
+	Ports   []*PortMap
+
+	// Lookup retrieves the service when reconnecting, since a restarted
+	// service may have a new address.
+	Lookup func() (*schemas.Service, error)
+
+	client    *ssh.Client
+	listeners []net.Listener
+	mutex     sync.Mutex
+	done      chan struct{}
+}
+
+// NewForwarder creates a forwarder.
+func NewForwarder(service *schemas.Service, ports []*PortMap) *Forwarder {
+	return &Forwarder{
+		Service:   service,
+		Ports:     ports,
+		listeners: make([]net.Listener, 0),
+		done:      make(chan struct{}),
+	}
+}
+
+// Start connects to the service and listens on the local ports.
+func (fwd *Forwarder) Start() error {
+	err := fwd.connect()
+	if err != nil {
+		return err
+	}
+
+	for _, port := range fwd.Ports {
+		listener, err := net.Listen("tcp", "127.0.0.1:"+port.Local)
+		if err != nil {
+			fwd.Close()
+			return errors.NewStackError(err)
+		}
+		fwd.listeners = append(fwd.listeners, listener)
+
+		go fwd.accept(listener, port)
+	}
+
+	return nil
+}
+
+// Close stops listening and closes the connection.
+func (fwd *Forwarder) Close() error {
+	close(fwd.done)
+
+	for _, listener := range fwd.listeners {
+		listener.Close()
+	}
+
+	fwd.mutex.Lock()
+	defer fwd.mutex.Unlock()
+	if fwd.client != nil {
+		return fwd.client.Close()
+	}
+
+	return nil
+}
+
+// connect opens the ssh connection, and reconnects when it drops.
+func (fwd *Forwarder) connect() error {
+	fwd.mutex.Lock()
+	service := fwd.Service
+	fwd.mutex.Unlock()
+
+	client, err := dial(service)
+	if err != nil {
+		return err
+	}
+
+	fwd.mutex.Lock()
+	fwd.client = client
+	fwd.mutex.Unlock()
+
+	go func() {
+		client.Wait()
+
+		select {
+		case <-fwd.done:
+			return
+		default:
+		}
+
+		log.Println("yellow", "Connection to", service.Name, "lost. Attempting to re-connect...")
+		fwd.reconnect()
+	}()
+
+	return nil
+}
+
+// reconnect attempts to connect with a backoff until connected or closed.
+func (fwd *Forwarder) reconnect() {
+	wait := time.Second
+
+	for {
+		select {
+		case <-fwd.done:
+			return
+		case <-time.After(wait):
+		}
+
+		if fwd.Lookup != nil {
+			service, err := fwd.Lookup()
+			if err == nil && service != nil {
+				fwd.mutex.Lock()
+				fwd.Service = service
+				fwd.mutex.Unlock()
+			}
+		}
+
+		err := fwd.connect()
+		fwd.mutex.Lock()
+		name := fwd.Service.Name
+		fwd.mutex.Unlock()
+		if err == nil {
+			log.Println("magenta", "Re-connected to", name+".")
+			return
+		}
+		log.Debug("Re-connecting to", name, "failed:", err)
+
+		wait *= 2
+		if wait > 30*time.Second {
+			wait = 30 * time.Second
+		}
+	}
+}
+
+// accept forwards connections from the listener until it's closed.
+func (fwd *Forwarder) accept(listener net.Listener, port *PortMap) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go fwd.forward(conn, port)
+	}
+}
+
+// forward copies data between a local connection and the remote port.
+func (fwd *Forwarder) forward(conn net.Conn, port *PortMap) {
+	defer conn.Close()
+
+	fwd.mutex.Lock()
+	client := fwd.client
+	name := fwd.Service.Name
+	fwd.mutex.Unlock()
+
+	remote, err := client.Dial("tcp", "127.0.0.1:"+port.Remote)
+	if err != nil {
+		log.Debug("Forwarding", port, "to", name, "failed:", err)
+		return
+	}
+	defer remote.Close()
+
+	// Finish when either side is done.
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(remote, conn)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(conn, remote)
+		done <- struct{}{}
+	}()
+
+	<-done
+}
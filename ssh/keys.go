@@ -0,0 +1,89 @@
+// Copyright 2013-2014 Bowery, Inc.
+package ssh
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.google.com/p/go.crypto/ssh"
+	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/gopackages/sys"
+)
+
+var env = os.Getenv("ENV")
+
+// KeyPath is the path to the developers private key, it's stored next to
+// their user config. The public key is at the same path with a .pub suffix.
+func KeyPath() string {
+	path := ".bowerykey"
+	if env == "development" {
+		path = ".bowerydevkey"
+	}
+
+	return filepath.Join(os.Getenv(sys.HomeVar), path)
+}
+
+// GetKey retrieves the developers private key.
+func GetKey() (ssh.Signer, error) {
+	pemBytes, err := ioutil.ReadFile(KeyPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.ErrNoKey
+		}
+
+		return nil, errors.NewStackError(err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(pemBytes)
+	if err != nil {
+		return nil, errors.NewStackError(err)
+	}
+
+	return signer, nil
+}
+
+// CreateKey retrieves the developers private key, generating and saving a new
+// keypair if none exists.
+func CreateKey() (ssh.Signer, error) {
+	signer, err := GetKey()
+	if err != errors.ErrNoKey {
+		return signer, err
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, errors.NewStackError(err)
+	}
+
+	signer, err = ssh.NewSignerFromKey(key)
+	if err != nil {
+		return nil, errors.NewStackError(err)
+	}
+
+	// Write the private key only readable by the developer.
+	pemBytes := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+	err = ioutil.WriteFile(KeyPath(), pemBytes, 0600)
+	if err != nil {
+		return nil, errors.NewStackError(err)
+	}
+
+	err = ioutil.WriteFile(KeyPath()+".pub", AuthorizedKey(signer), 0644)
+	if err != nil {
+		return nil, errors.NewStackError(err)
+	}
+
+	return signer, nil
+}
+
+// AuthorizedKey gets the public key for a signer in the authorized_keys format.
+func AuthorizedKey(signer ssh.Signer) []byte {
+	return ssh.MarshalAuthorizedKey(signer.PublicKey())
+}
@@ -0,0 +1,157 @@
+// Copyright 2013-2014 Bowery, Inc.
+package ssh
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"code.google.com/p/go.crypto/ssh"
+	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/gopackages/schemas"
+)
+
+// KnownHostsPath is the path to the apps pinned host keys.
+var KnownHostsPath = filepath.Join(".bowery", "known_hosts")
+
+// hostKeyChecker verifies host keys for a service. Unknown hosts have their
+// key pinned, and known hosts must match the pinned key.
+type hostKeyChecker struct {
+	Service *schemas.Service
+	Changed bool
+}
+
+// Check is the host key callback for the ssh client config.
+func (checker *hostKeyChecker) Check(hostname string, remote net.Addr, key ssh.PublicKey) error {
+	hosts, err := readKnownHosts()
+	if err != nil {
+		return err
+	}
+	addr := checker.Service.SSHAddr
+	line := string(bytes.TrimSpace(ssh.MarshalAuthorizedKey(key)))
+
+	pinned, ok := hosts[addr]
+	if !ok {
+		hosts[addr] = line
+		return writeKnownHosts(hosts)
+	}
+
+	if pinned != line {
+		checker.Changed = true
+		return errors.Newf(errors.ErrHostKeyChangedTmpl, checker.Service.Name, addr)
+	}
+
+	return nil
+}
+
+// RemoveHostKey removes the pinned host key for an address, used when a
+// service is replaced.
+func RemoveHostKey(addr string) error {
+	hosts, err := readKnownHosts()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := hosts[addr]; !ok {
+		return nil
+	}
+	delete(hosts, addr)
+
+	return writeKnownHosts(hosts)
+}
+
+// readKnownHosts reads the pinned keys by host address.
+func readKnownHosts() (map[string]string, error) {
+	hosts := make(map[string]string)
+
+	file, err := os.Open(KnownHostsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return hosts, nil
+		}
+
+		return nil, errors.NewStackError(err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.SplitN(strings.TrimSpace(scanner.Text()), " ", 2)
+		if len(fields) < 2 {
+			continue
+		}
+
+		hosts[hostAddr(fields[0])] = fields[1]
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, errors.NewStackError(err)
+	}
+
+	return hosts, nil
+}
+
+// writeKnownHosts writes the pinned keys sorted by host, creating the file
+// if needed.
+func writeKnownHosts(hosts map[string]string) error {
+	names := make([]string, 0, len(hosts))
+	keys := make(map[string]string, len(hosts))
+	for addr, key := range hosts {
+		name := hostName(addr)
+		names = append(names, name)
+		keys[name] = key
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		buf.WriteString(name + " " + keys[name] + "\n")
+	}
+
+	err := os.MkdirAll(filepath.Dir(KnownHostsPath), os.ModePerm|os.ModeDir)
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+
+	file, err := os.OpenFile(KnownHostsPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+	defer file.Close()
+
+	_, err = buf.WriteTo(file)
+	if err != nil {
+		return errors.NewStackError(err)
+	}
+
+	return nil
+}
+
+// hostName gets the name used for an address in known_hosts, the OpenSSH
+// format is the host for port 22 and [host]:port otherwise.
+func hostName(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	if port == "22" {
+		return host
+	}
+
+	return "[" + host + "]:" + port
+}
+
+// hostAddr gets the address for a name in known_hosts.
+func hostAddr(name string) string {
+	host, port, err := net.SplitHostPort(name)
+	if err != nil {
+		return net.JoinHostPort(name, "22")
+	}
+
+	return net.JoinHostPort(host, port)
+}
@@ -15,93 +15,170 @@ import (
 	"github.com/Bowery/gopackages/schemas"
 )
 
-// Shell opens a shell connection on the servives ssh address.
-func Shell(app *schemas.Application, service *schemas.Service) error {
-	// Make sure we're in raw mode.
-	termState, err := terminal.MakeRaw(int(os.Stdin.Fd()))
-	if err != nil {
-		if prompt.IsNotTerminal(err) {
-			return errors.ErrIORedirection
-		}
-
-		return errors.NewStackError(err)
+// Shell opens a shell connection on the servives ssh address. If stdin or
+// stdout aren't a terminal the shell runs without a pty, reading commands
+// from stdin. The exit status of the shell is returned.
+func Shell(app *schemas.Application, service *schemas.Service) (int, error) {
+	if !IsTerminal() {
+		return Exec(service, "", false)
 	}
-	defer terminal.Restore(int(os.Stdin.Fd()), termState)
 
-	// Get terminal size.
-	cols, rows, err := terminal.GetSize(int(os.Stdout.Fd()))
-	if err != nil {
-		if prompt.IsNotTerminal(err) {
-			return errors.ErrIORedirection
+	log.Println("magenta", "Welcome to Bowery Services.")
+	log.Println("magenta", "---------------------------------------------")
+	log.Println("magenta", "Name:", service.Name)
+	log.Println("magenta", "Application:", app.ID)
+	log.Println("magenta", "Time:", time.Now())
+	log.Println("magenta", "---------------------------------------------")
+
+	return Exec(service, "", true)
+}
+
+// IsTerminal checks if stdin and stdout are both connected to a terminal.
+func IsTerminal() bool {
+	return terminal.IsTerminal(int(os.Stdin.Fd())) && terminal.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// Exec runs a single command on the services ssh address, piping stdin,
+// stdout and stderr. If the command is empty a shell is started instead. If
+// tty is true a pty is requested for the command. The exit status of the
+// command is returned.
+func Exec(service *schemas.Service, command string, tty bool) (int, error) {
+	var win Window
+	size := WindowSize{Cols: 80, Rows: 24}
+
+	if tty {
+		// Make sure we're in raw mode.
+		termState, err := terminal.MakeRaw(int(os.Stdin.Fd()))
+		if err != nil {
+			if prompt.IsNotTerminal(err) {
+				return 1, errors.ErrIORedirection
+			}
+
+			return 1, errors.NewStackError(err)
 		}
+		defer terminal.Restore(int(os.Stdin.Fd()), termState)
+
+		// Get terminal size, and watch for resizes.
+		win = NewWindow(os.Stdout)
+		defer win.Close()
+		size, err = win.Size()
+		if err != nil {
+			if prompt.IsNotTerminal(err) {
+				return 1, errors.ErrIORedirection
+			}
 
-		return errors.NewStackError(err)
+			return 1, errors.NewStackError(err)
+		}
 	}
 
-	// Open an SSH connection to the address.
-	config := &ssh.ClientConfig{User: "root", Auth: []ssh.AuthMethod{
-		ssh.Password("password"),
-	}}
-	client, err := ssh.Dial("tcp", service.SSHAddr, config)
+	client, err := dial(service)
 	if err != nil {
-		return errors.NewStackError(err)
+		return 1, err
 	}
 	defer client.Close()
 
 	// Start a session on the client.
 	session, err := client.NewSession()
 	if err != nil {
-		return errors.NewStackError(err)
+		return 1, errors.NewStackError(err)
 	}
 	defer session.Close()
-	session.Stdout = prompt.NewAnsiWriter(os.Stdout)
-	session.Stderr = prompt.NewAnsiWriter(os.Stderr)
+	session.Stdout = os.Stdout
+	session.Stderr = os.Stderr
+	if tty {
+		session.Stdout = prompt.NewAnsiWriter(os.Stdout)
+		session.Stderr = prompt.NewAnsiWriter(os.Stderr)
+	}
 
-	// Create a stdin pipe copying os.Stdin to it.
+	// Create a stdin pipe copying os.Stdin to it, closing it once stdin is
+	// done so the command gets EOF.
 	stdin, err := session.StdinPipe()
 	if err != nil {
-		return errors.NewStackError(err)
+		return 1, errors.NewStackError(err)
 	}
 	defer stdin.Close()
 
 	go func() {
-		io.Copy(stdin, prompt.NewAnsiReader(os.Stdin))
+		var in io.Reader = os.Stdin
+		if tty {
+			in = prompt.NewAnsiReader(os.Stdin)
+		}
+
+		io.Copy(stdin, in)
+		stdin.Close()
 	}()
 
-	log.Println("magenta", "Welcome to Bowery Services.")
-	log.Println("magenta", "---------------------------------------------")
-	log.Println("magenta", "Name:", service.Name)
-	log.Println("magenta", "Application:", app.ID)
-	log.Println("magenta", "Time:", time.Now())
-	log.Println("magenta", "---------------------------------------------")
+	if tty {
+		termModes := ssh.TerminalModes{
+			ssh.ECHO:          1,
+			ssh.TTY_OP_ISPEED: 14400,
+			ssh.TTY_OP_OSPEED: 14400,
+		}
 
-	// Start a shell session.
-	termModes := ssh.TerminalModes{
-		ssh.ECHO:          1,
-		ssh.TTY_OP_ISPEED: 14400,
-		ssh.TTY_OP_OSPEED: 14400,
+		err = session.RequestPty("xterm", size.Rows, size.Cols, termModes)
+		if err != nil {
+			return 1, errors.NewStackError(err)
+		}
+
+		// Send window changes until the command exits.
+		done := make(chan struct{})
+		defer close(done)
+		go watchWindow(session, win, done)
 	}
-	err = session.RequestPty("xterm", rows, cols, termModes)
-	if err == nil {
+
+	// Run the command, or a shell if none is given.
+	if command == "" {
 		err = session.Shell()
+		if err == nil {
+			err = session.Wait()
+		}
+	} else {
+		err = session.Run(command)
+	}
+
+	// Get the exit status of the command.
+	if err != nil && err != io.EOF {
+		exitErr, ok := err.(*ssh.ExitError)
+		if ok {
+			// Ignore the error for a shell if it has an empty message, this occurs
+			// when you do CTRL+c and then run exit which isn't an actual error.
+			if command == "" && exitErr.Msg() == "" {
+				return 0, nil
+			}
+
+			return exitErr.ExitStatus(), nil
+		}
+
+		return 1, errors.NewStackError(err)
 	}
+
+	return 0, nil
+}
+
+// dial opens an SSH connection to the services ssh address, authenticating
+// with the developers key and verifying the pinned host key.
+func dial(service *schemas.Service) (*ssh.Client, error) {
+	signer, err := GetKey()
 	if err != nil {
-		return errors.NewStackError(err)
+		return nil, err
 	}
 
-	// Wait for the session.
-	err = session.Wait()
-	if err != nil && err != io.EOF {
-		// Ignore the error if it's an ExitError with an empty message,
-		// this occurs when you do CTRL+c and then run exit cmd which isn't an
-		// actual error.
-		waitMsg, ok := err.(*ssh.ExitError)
-		if ok && waitMsg.Msg() == "" {
-			return nil
+	checker := &hostKeyChecker{Service: service}
+	config := &ssh.ClientConfig{
+		User:            "root",
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: checker.Check,
+	}
+
+	client, err := ssh.Dial("tcp", service.SSHAddr, config)
+	if err != nil {
+		// Host key mismatches aren't stack errors, the developer must act.
+		if checker.Changed {
+			return nil, errors.Newf(errors.ErrHostKeyChangedTmpl, service.Name, service.SSHAddr)
 		}
 
-		return errors.NewStackError(err)
+		return nil, errors.NewStackError(err)
 	}
 
-	return nil
+	return client, nil
 }
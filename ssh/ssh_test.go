@@ -0,0 +1,339 @@
+// Copyright 2013-2014 Bowery, Inc.
+package ssh
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/rsa"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"code.google.com/p/go.crypto/ssh"
+	"github.com/Bowery/gopackages/schemas"
+)
+
+var testService = &schemas.Service{
+	Name:    "testservice",
+	SSHAddr: "0.0.0.0:4",
+}
+
+func newTestKey(t *testing.T) ssh.PublicKey {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signer.PublicKey()
+}
+
+// known_hosts.go
+func TestHostKeyChecker(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bowery_ssh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	KnownHostsPath = filepath.Join(dir, ".bowery", "known_hosts")
+
+	key := newTestKey(t)
+	checker := &hostKeyChecker{Service: testService}
+
+	// First connection pins the key, and later ones must match it.
+	if err := checker.Check(testService.SSHAddr, nil, key); err != nil {
+		t.Fatal(err)
+	}
+	if err := checker.Check(testService.SSHAddr, nil, key); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checker.Check(testService.SSHAddr, nil, newTestKey(t)); err == nil || !checker.Changed {
+		t.Error("Changed host key was accepted.")
+	}
+
+	// Removing the key trusts the next one.
+	if err := RemoveHostKey(testService.SSHAddr); err != nil {
+		t.Fatal(err)
+	}
+	checker = &hostKeyChecker{Service: testService}
+	if err := checker.Check(testService.SSHAddr, nil, newTestKey(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Hosts are written sorted in the OpenSSH format.
+	other := &hostKeyChecker{Service: &schemas.Service{Name: "other", SSHAddr: "0.0.0.0:22"}}
+	if err := other.Check(other.Service.SSHAddr, nil, newTestKey(t)); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(KnownHostsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "0.0.0.0 ssh-rsa ") ||
+		!strings.HasPrefix(lines[1], "[0.0.0.0]:4 ssh-rsa ") {
+		t.Error("known_hosts has invalid lines", lines)
+	}
+
+	hosts, err := readKnownHosts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := hosts[testService.SSHAddr]; !ok || len(hosts) != 2 {
+		t.Error("readKnownHosts read invalid hosts", hosts)
+	}
+}
+
+// testWindow is a window resized by the test.
+type testWindow struct {
+	size    WindowSize
+	resized chan struct{}
+	mutex   sync.Mutex
+}
+
+func (win *testWindow) Size() (WindowSize, error) {
+	win.mutex.Lock()
+	defer win.mutex.Unlock()
+
+	return win.size, nil
+}
+
+func (win *testWindow) Resize(size WindowSize) {
+	win.mutex.Lock()
+	win.size = size
+	win.mutex.Unlock()
+
+	win.resized <- struct{}{}
+}
+
+func (win *testWindow) Resized() <-chan struct{} {
+	return win.resized
+}
+
+func (win *testWindow) Close() error {
+	return nil
+}
+
+// testRequester records the requests sent.
+type testRequester struct {
+	requests chan []byte
+}
+
+func (req *testRequester) SendRequest(name string, wantReply bool, payload []byte) (bool, error) {
+	if name == "window-change" {
+		req.requests <- payload
+	}
+
+	return true, nil
+}
+
+// window.go
+func TestWatchWindow(t *testing.T) {
+	win := &testWindow{size: WindowSize{Cols: 80, Rows: 24}, resized: make(chan struct{})}
+	session := &testRequester{requests: make(chan []byte, 2)}
+	done := make(chan struct{})
+	defer close(done)
+	go watchWindow(session, win, done)
+
+	// Unchanged sizes shouldn't send a request.
+	win.Resize(WindowSize{Cols: 80, Rows: 24})
+	win.Resize(WindowSize{Cols: 120, Rows: 40})
+
+	payload := <-session.requests
+	expected := windowChangePayload(WindowSize{Cols: 120, Rows: 40})
+	if string(payload) != string(expected) {
+		t.Error("Window change payload was", payload, "expected", expected)
+	}
+
+	if len(session.requests) > 0 {
+		t.Error("Window change sent for an unchanged size.")
+	}
+}
+
+// forward.go
+func TestParsePortMap(t *testing.T) {
+	port, err := ParsePortMap("15432:5432")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if port.Local != "15432" || port.Remote != "5432" {
+		t.Error("ParsePortMap parsed", port)
+	}
+
+	port, err = ParsePortMap("6379")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if port.Local != "6379" || port.Remote != "6379" {
+		t.Error("ParsePortMap parsed", port)
+	}
+
+	for _, str := range []string{"", "abc", "80:", "0", "70000", "1:2:3"} {
+		if _, err := ParsePortMap(str); err == nil {
+			t.Error("ParsePortMap accepted", str)
+		}
+	}
+}
+
+// copy.go
+func TestTarRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bowery_ssh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "src")
+	err = os.MkdirAll(filepath.Join(src, "sub"), os.ModePerm|os.ModeDir)
+	if err == nil {
+		err = ioutil.WriteFile(filepath.Join(src, "sub", "file.txt"), []byte("contents"), 0644)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	uploaded := make([]string, 0)
+	err = writeTar(&buf, src, "renamed", func(name string) {
+		uploaded = append(uploaded, name)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uploaded) != 1 || uploaded[0] != "renamed/sub/file.txt" {
+		t.Error("writeTar copied", uploaded)
+	}
+
+	dest := filepath.Join(dir, "dest")
+	err = untar(&buf, "renamed", dest, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents, err := ioutil.ReadFile(filepath.Join(dest, "sub", "file.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(contents) != "contents" {
+		t.Error("untar wrote", string(contents))
+	}
+}
+
+// copy.go
+func TestUntarUnsafeLinks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bowery_ssh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	outside := filepath.Join(dir, "outside")
+	err = os.MkdirAll(outside, os.ModePerm|os.ModeDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archives := map[string][]*tar.Header{
+		"link outside": {
+			{Name: "base/link", Typeflag: tar.TypeSymlink, Linkname: "../outside"},
+			{Name: "base/link/file.txt", Typeflag: tar.TypeReg, Mode: 0644, Size: 4},
+		},
+		"absolute link": {
+			{Name: "base/link", Typeflag: tar.TypeSymlink, Linkname: outside},
+		},
+		"existing link": {
+			{Name: "base/existing/file.txt", Typeflag: tar.TypeReg, Mode: 0644, Size: 4},
+		},
+	}
+
+	for name, headers := range archives {
+		dest := filepath.Join(dir, "dest")
+		os.RemoveAll(dest)
+		err = os.MkdirAll(dest, os.ModePerm|os.ModeDir)
+		if err == nil {
+			err = os.Symlink(outside, filepath.Join(dest, "existing"))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		gzipWriter := gzip.NewWriter(&buf)
+		tarWriter := tar.NewWriter(gzipWriter)
+		for _, header := range headers {
+			tarWriter.WriteHeader(header)
+			if header.Size > 0 {
+				tarWriter.Write([]byte("evil"))
+			}
+		}
+		tarWriter.Close()
+		gzipWriter.Close()
+
+		err = untar(&buf, "base", dest, nil)
+		if err == nil {
+			t.Error("untar should fail for an archive with a", name)
+		}
+
+		files, err := ioutil.ReadDir(outside)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) > 0 {
+			t.Error("untar wrote outside of dest for an archive with a", name)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	if quoted := quote("it's"); quoted != `'it'\''s'` {
+		t.Error("quote returned", quoted)
+	}
+
+	if quoted := QuoteArgs([]string{"echo", "a b", "$HOME"}); quoted != `'echo' 'a b' '$HOME'` {
+		t.Error("QuoteArgs returned", quoted)
+	}
+}
+
+// config.go
+func TestReplaceConfig(t *testing.T) {
+	app := &schemas.Application{
+		ID:       "5303a1636462d4d468000002",
+		Name:     "someapp",
+		Services: []*schemas.Service{testService},
+	}
+	block, err := Config(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(block, "Host someapp-testservice\n") || !strings.Contains(block, "  Port 4\n") {
+		t.Error("Config created", block)
+	}
+
+	knownHosts, err := filepath.Abs(KnownHostsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(block, "  UserKnownHostsFile "+configQuote(knownHosts)+"\n") {
+		t.Error("Config should use the pinned host keys", block)
+	}
+
+	existing := "Host other\n  HostName example.com\n"
+	contents := replaceConfig(existing, app, block)
+	if contents != existing+block {
+		t.Error("replaceConfig didn't append the block", contents)
+	}
+
+	// Replacing again shouldn't duplicate the block.
+	if replaced := replaceConfig(contents, app, block); replaced != contents {
+		t.Error("replaceConfig isn't idempotent", replaced)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2013-2014 Bowery, Inc.
+package ssh
+
+import (
+	"encoding/binary"
+	"os"
+
+	"code.google.com/p/go.crypto/ssh/terminal"
+)
+
+// WindowSize is the size of a terminal window.
+type WindowSize struct {
+	Cols int
+	Rows int
+}
+
+// Window is a terminal window that notifies when it may have been resized.
+type Window interface {
+	// Size retrieves the current size of the window.
+	Size() (WindowSize, error)
+
+	// Resized receives when the window may have been resized.
+	Resized() <-chan struct{}
+
+	// Close stops resize notifications.
+	Close() error
+}
+
+// requester sends requests on an ssh channel, e.g. a session.
+type requester interface {
+	SendRequest(name string, wantReply bool, payload []byte) (bool, error)
+}
+
+// terminalSize retrieves the size of the terminal connected to out.
+func terminalSize(out *os.File) (WindowSize, error) {
+	cols, rows, err := terminal.GetSize(int(out.Fd()))
+	return WindowSize{Cols: cols, Rows: rows}, err
+}
+
+// watchWindow sends window-change requests to the session when the window
+// size changes, until done is closed.
+func watchWindow(session requester, win Window, done <-chan struct{}) {
+	last, _ := win.Size()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-win.Resized():
+			size, err := win.Size()
+			if err != nil || size == last {
+				continue
+			}
+			last = size
+
+			// Ignore errors, a failed resize shouldn't end the session.
+			session.SendRequest("window-change", false, windowChangePayload(size))
+		}
+	}
+}
+
+// windowChangePayload creates the payload for a window-change request,
+// see RFC 4254 section 6.7.
+func windowChangePayload(size WindowSize) []byte {
+	payload := make([]byte, 16)
+	binary.BigEndian.PutUint32(payload, uint32(size.Cols))
+	binary.BigEndian.PutUint32(payload[4:], uint32(size.Rows))
+
+	return payload
+}
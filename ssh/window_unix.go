@@ -0,0 +1,60 @@
+// +build linux darwin
+
+// Copyright 2013-2014 Bowery, Inc.
+package ssh
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// sigwinchWindow is a window notified of resizes via SIGWINCH.
+type sigwinchWindow struct {
+	out     *os.File
+	signals chan os.Signal
+	resized chan struct{}
+	done    chan struct{}
+}
+
+// NewWindow creates a window for the terminal connected to out.
+func NewWindow(out *os.File) Window {
+	win := &sigwinchWindow{
+		out:     out,
+		signals: make(chan os.Signal, 1),
+		resized: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	signal.Notify(win.signals, syscall.SIGWINCH)
+
+	go func() {
+		for {
+			select {
+			case <-win.signals:
+				select {
+				case win.resized <- struct{}{}:
+				case <-win.done:
+					return
+				}
+			case <-win.done:
+				return
+			}
+		}
+	}()
+
+	return win
+}
+
+func (win *sigwinchWindow) Size() (WindowSize, error) {
+	return terminalSize(win.out)
+}
+
+func (win *sigwinchWindow) Resized() <-chan struct{} {
+	return win.resized
+}
+
+func (win *sigwinchWindow) Close() error {
+	signal.Stop(win.signals)
+	close(win.done)
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2013-2014 Bowery, Inc.
+package ssh
+
+import (
+	"os"
+	"time"
+)
+
+// pollWindow is a window checked for resizes on an interval, since Windows
+// has no resize signal.
+type pollWindow struct {
+	out     *os.File
+	ticker  *time.Ticker
+	resized chan struct{}
+	done    chan struct{}
+}
+
+// NewWindow creates a window for the terminal connected to out.
+func NewWindow(out *os.File) Window {
+	win := &pollWindow{
+		out:     out,
+		ticker:  time.NewTicker(250 * time.Millisecond),
+		resized: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	go func() {
+		for {
+			select {
+			case <-win.ticker.C:
+				select {
+				case win.resized <- struct{}{}:
+				case <-win.done:
+					return
+				}
+			case <-win.done:
+				return
+			}
+		}
+	}()
+
+	return win
+}
+
+func (win *pollWindow) Size() (WindowSize, error) {
+	return terminalSize(win.out)
+}
+
+func (win *pollWindow) Resized() <-chan struct{} {
+	return win.resized
+}
+
+func (win *pollWindow) Close() error {
+	win.ticker.Stop()
+	close(win.done)
+	return nil
+}
@@ -8,11 +8,13 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Bowery/bowery/db"
 	"github.com/Bowery/bowery/delancey"
 	"github.com/Bowery/bowery/errors"
+	"github.com/Bowery/bowery/hooks"
 	"github.com/Bowery/gopackages/log"
 	"github.com/Bowery/gopackages/schemas"
 	"github.com/Bowery/gopackages/tar"
@@ -31,9 +33,13 @@ func (ev *Event) String() string {
 
 // Watcher contains an fs watcher and handles the syncing to a service.
 type Watcher struct {
-	Path    string
-	Service *schemas.Service
-	done    chan struct{}
+	Path        string
+	Service     *schemas.Service
+	Hook        func(*schemas.Service, string)
+	done        chan struct{}
+	hookRunning bool
+	hookPending bool
+	hookMutex   sync.Mutex
 }
 
 // NewWatcher creates a watcher.
@@ -52,6 +58,7 @@ func (watcher *Watcher) Start(evChan chan *Event, errChan chan error) {
 	}
 	stats := make(map[string]os.FileInfo)
 	found := make([]string, 0)
+	changed := false
 
 	ignores, err := db.GetIgnores(watcher.Path)
 	if err != nil {
@@ -140,6 +147,7 @@ func (watcher *Watcher) Start(evChan chan *Event, errChan chan error) {
 		evChan <- &Event{watcher.Service.Name, status, rel}
 		stats[path] = info
 		found = append(found, path)
+		changed = true
 		return nil
 	}
 
@@ -191,13 +199,48 @@ func (watcher *Watcher) Start(evChan chan *Event, errChan chan error) {
 			}
 
 			evChan <- &Event{watcher.Service.Name, "delete", rel}
+			changed = true
+		}
+
+		// Run the sync hook once for the batch of changes.
+		if changed && watcher.Hook != nil {
+			watcher.runHook()
 		}
 
 		found = make([]string, 0)
+		changed = false
 		<-time.After(500 * time.Millisecond)
 	}
 }
 
+// runHook runs the sync hook without blocking the watcher. If the hook is
+// still running for an earlier batch it's ran again once it finishes.
+func (watcher *Watcher) runHook() {
+	watcher.hookMutex.Lock()
+	defer watcher.hookMutex.Unlock()
+
+	if watcher.hookRunning {
+		watcher.hookPending = true
+		return
+	}
+	watcher.hookRunning = true
+
+	go func() {
+		for {
+			watcher.Hook(watcher.Service, hooks.Sync)
+
+			watcher.hookMutex.Lock()
+			if !watcher.hookPending {
+				watcher.hookRunning = false
+				watcher.hookMutex.Unlock()
+				return
+			}
+			watcher.hookPending = false
+			watcher.hookMutex.Unlock()
+		}
+	}()
+}
+
 // Upload sends the paths contents to the service compressed.
 func (watcher *Watcher) Upload() error {
 	var (
@@ -298,6 +341,10 @@ type Syncer struct {
 	Upload   chan *schemas.Service
 	Error    chan error
 	Watchers []*Watcher
+
+	// Hook is called after the initial upload and each batch of synced
+	// changes for a service, with the hook event.
+	Hook func(*schemas.Service, string)
 }
 
 // NewSyncer creates a syncer.
@@ -313,6 +360,7 @@ func NewSyncer() *Syncer {
 // Watch starts watching the given path and updates changes to the service.
 func (syncer *Syncer) Watch(path string, service *schemas.Service) {
 	watcher := NewWatcher(path, service)
+	watcher.Hook = syncer.Hook
 	syncer.Watchers = append(syncer.Watchers, watcher)
 
 	// Do the actual event management, and the inital upload.
@@ -322,6 +370,9 @@ func (syncer *Syncer) Watch(path string, service *schemas.Service) {
 			syncer.Error <- err
 			return
 		}
+		if syncer.Hook != nil {
+			syncer.Hook(service, hooks.Upload)
+		}
 		syncer.Upload <- service
 
 		watcher.Start(syncer.Event, syncer.Error)